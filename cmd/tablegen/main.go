@@ -0,0 +1,79 @@
+// Command tablegen generates Go structs, loaders and constants from table files.
+//
+// Usage:
+//
+//	//go:generate go run github.com/stepupdream/go-support-tool/cmd/tablegen -output tables_gen.go -schema ./schema -constants item_types:name ./data/item_types.csv ./data/items.csv
+//
+// The schema of a table is the file of the same name in the schema directory. A table without a schema file is generated
+// without types, so every column except the id is a string.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/stepupdream/go-support-tool/codegen"
+	"github.com/stepupdream/go-support-tool/file"
+	"github.com/stepupdream/go-support-tool/table"
+)
+
+func main() {
+	packageName := flag.String("package", os.Getenv("GOPACKAGE"), "package name of the generated file (default: $GOPACKAGE)")
+	output := flag.String("output", "tables_gen.go", "path of the generated file")
+	schemaDirectoryPath := flag.String("schema", "", "directory of the schema files named after the tables")
+	constants := flag.String("constants", "", "comma separated table:column pairs whose column values name the id constants")
+	flag.Parse()
+
+	if *packageName == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	constantColumns := map[string]string{}
+	for _, pair := range strings.Split(*constants, ",") {
+		if pair == "" {
+			continue
+		}
+		tableName, column, ok := strings.Cut(pair, ":")
+		if !ok {
+			log.Fatalln("Constants must be table:column : " + pair)
+		}
+		constantColumns[tableName] = column
+	}
+
+	var tables []*codegen.Table
+	for _, filePath := range flag.Args() {
+		schema, err := loadSchema(*schemaDirectoryPath, filePath)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		t, err := codegen.LoadTable(filePath, schema)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		t.ConstantColumn = constantColumns[t.Name]
+		tables = append(tables, t)
+	}
+
+	if err := codegen.WriteFile(*output, *packageName, tables); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// loadSchema Load the schema file of the table file in the schema directory. If it does not exist, return nil.
+func loadSchema(schemaDirectoryPath string, filePath string) (*table.Schema, error) {
+	if schemaDirectoryPath == "" {
+		return nil, nil
+	}
+
+	schemaPath := filepath.Join(schemaDirectoryPath, filepath.Base(filePath))
+	if !file.Exists(schemaPath) {
+		return nil, nil
+	}
+
+	return table.LoadSchema(schemaPath)
+}
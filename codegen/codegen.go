@@ -0,0 +1,384 @@
+package codegen
+
+import (
+	"bytes"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/go-support-tool/array"
+	"github.com/stepupdream/go-support-tool/delimited"
+	"github.com/stepupdream/go-support-tool/file"
+	"github.com/stepupdream/go-support-tool/table"
+)
+
+var columnTypeNames = map[table.ColumnType]string{
+	table.TypeString: "table.TypeString",
+	table.TypeInt:    "table.TypeInt",
+	table.TypeFloat:  "table.TypeFloat",
+	table.TypeBool:   "table.TypeBool",
+	table.TypeEnum:   "table.TypeEnum",
+	table.TypeDate:   "table.TypeDate",
+}
+
+var emptyPolicyNames = map[table.EmptyPolicy]string{
+	table.EmptyInherit:   "table.EmptyInherit",
+	table.EmptyReject:    "table.EmptyReject",
+	table.EmptyAsString:  "table.EmptyAsString",
+	table.EmptyAsAbsent:  "table.EmptyAsAbsent",
+	table.EmptyAsDefault: "table.EmptyAsDefault",
+}
+
+// Table is a table whose Go source is generated.
+type Table struct {
+	// Name is the name of the table. (ex. item_types)
+	Name string
+	// TypeName is the name of the struct of a row. If empty, the singular pascal case of Name is used. (ex. ItemType)
+	TypeName string
+	// Columns is the headers of the table file in order.
+	Columns []string
+	// Schema is the schema of the table. If nil, every column except the id is a string.
+	Schema *table.Schema
+	// ConstantColumn is the column whose values name a constant for the id of each row. (ex. name -> ItemTypeWeapon = 3)
+	// If empty, no constant is generated.
+	ConstantColumn string
+	// Rows is the values of the table, used for the constants.
+	Rows map[table.Key]string
+}
+
+// LoadTable Load the table file to generate. The name of the table is the base name of the file.
+// If the schema is nil, the values are not checked.
+//
+//goland:noinspection GoUnusedExportedFunction
+func LoadTable(filePath string, schema *table.Schema) (*Table, error) {
+	rows, err := delimited.Load(filePath, true, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("Empty table file : " + filePath)
+	}
+
+	valueMap, err := table.LoadMapWithSchema(filePath, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Table{
+		Name:    file.BaseFileName(filePath),
+		Columns: rows[0],
+		Schema:  schema,
+		Rows:    valueMap,
+	}, nil
+}
+
+// Generate Generate the formatted Go source of the tables.
+// For each table, it generates a struct of a row with `table` tags, a loader built on table.LoadMap
+// (table.LoadMapWithSchema with the literal of the schema when the table has a schema),
+// and the constants of the ids when ConstantColumn is set.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Generate(packageName string, tables []*Table) ([]byte, error) {
+	var body bytes.Buffer
+	usesTime := false
+	for _, t := range tables {
+		source, err := t.generate()
+		if err != nil {
+			return nil, errors.Wrap(err, "table : "+t.Name)
+		}
+		body.WriteString(source)
+		usesTime = usesTime || t.usesTime()
+	}
+
+	var source bytes.Buffer
+	source.WriteString("// Code generated by tablegen. DO NOT EDIT.\n\n")
+	source.WriteString("package " + packageName + "\n\n")
+	source.WriteString("import (\n")
+	if usesTime {
+		source.WriteString("\t\"time\"\n\n")
+	}
+	source.WriteString("\t\"github.com/stepupdream/go-support-tool/table\"\n)\n")
+	source.Write(body.Bytes())
+
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to format the generated source")
+	}
+
+	return formatted, nil
+}
+
+// WriteFile Generate the Go source of the tables and write it to the specified file.
+//
+//goland:noinspection GoUnusedExportedFunction
+func WriteFile(path string, packageName string, tables []*Table) error {
+	source, err := Generate(packageName, tables)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(path, source)
+}
+
+// generate Generate the struct, the loader and the constants of the table.
+func (t *Table) generate() (string, error) {
+	typeName := t.typeName()
+	loaderName := "Load" + toPascalCase(t.Name)
+
+	var builder strings.Builder
+	builder.WriteString("\n// " + typeName + " is a row of the " + t.Name + " table.\n")
+	builder.WriteString("type " + typeName + " struct {\n")
+	fieldNames := map[string]string{}
+	for _, column := range t.Columns {
+		fieldName := toPascalCase(column)
+		if other, ok := fieldNames[fieldName]; ok {
+			return "", errors.New("Columns have the same field name : " + other + " " + column)
+		}
+		fieldNames[fieldName] = column
+
+		tag, err := t.fieldTag(column)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString("\t" + fieldName + " " + t.fieldType(column) + " `table:" + strconv.Quote(tag) + "`\n")
+	}
+	builder.WriteString("}\n")
+
+	builder.WriteString("\n// " + loaderName + " Load the rows of the " + t.Name + " table from the specified file.\n")
+	builder.WriteString("func " + loaderName + "(filePath string) ([]" + typeName + ", error) {\n")
+	if t.Schema == nil {
+		builder.WriteString("\tvalueMap, err := table.LoadMap(filePath)\n")
+	} else {
+		builder.WriteString("\tvalueMap, err := table.LoadMapWithSchema(filePath, " + t.schemaLiteral() + ")\n")
+	}
+	builder.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n\n")
+	builder.WriteString("\treturn table.DecodeMap[" + typeName + "](valueMap)\n}\n")
+
+	if t.ConstantColumn != "" {
+		constants, err := t.generateConstants(typeName)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(constants)
+	}
+
+	return builder.String(), nil
+}
+
+// generateConstants Generate a constant for the id of each row, named by the value of the constant column.
+func (t *Table) generateConstants(typeName string) (string, error) {
+	if !array.Contains(t.Columns, t.ConstantColumn) {
+		return "", errors.New("Not found constant column : " + t.ConstantColumn)
+	}
+	if !t.isIntKey() {
+		return "", errors.New("Constants need a table with a single int key column")
+	}
+
+	var builder strings.Builder
+	builder.WriteString("\n// The ids of the " + t.Name + " table.\n")
+	builder.WriteString("const (\n")
+	constantNames := map[string]bool{}
+	for _, id := range table.PluckRowId(t.Rows) {
+		value := t.Rows[id.Key(t.ConstantColumn)]
+		constantName := typeName + toPascalCase(value)
+		if value == "" || constantNames[constantName] {
+			return "", errors.New("Constant name is empty or not unique : id : " + id.String() + " : " + value)
+		}
+		constantNames[constantName] = true
+
+		builder.WriteString("\t" + constantName + " = " + strconv.Itoa(id.Id) + "\n")
+	}
+	builder.WriteString(")\n")
+
+	return builder.String(), nil
+}
+
+// schemaLiteral Get the Go literal of the schema, so the generated loader checks the values as the schema does.
+func (t *Table) schemaLiteral() string {
+	var builder strings.Builder
+	builder.WriteString("&table.Schema{\n")
+	if len(t.Schema.KeyColumns) > 0 {
+		builder.WriteString("KeyColumns: " + stringSliceLiteral(t.Schema.KeyColumns) + ",\n")
+	}
+	if t.Schema.Empty != table.EmptyInherit {
+		builder.WriteString("Empty: " + emptyPolicyNames[t.Schema.Empty] + ",\n")
+	}
+	if t.Schema.NullMarker != "" {
+		builder.WriteString("NullMarker: " + strconv.Quote(t.Schema.NullMarker) + ",\n")
+	}
+	builder.WriteString("Columns: []table.Column{\n")
+	for _, column := range t.Schema.Columns {
+		fields := []string{"Name: " + strconv.Quote(column.Name), "Type: " + columnTypeNames[column.Type]}
+		if column.Nullable {
+			fields = append(fields, "Nullable: true")
+		}
+		if len(column.Values) > 0 {
+			fields = append(fields, "Values: "+stringSliceLiteral(column.Values))
+		}
+		if column.Layout != "" {
+			fields = append(fields, "Layout: "+strconv.Quote(column.Layout))
+		}
+		if column.Empty != table.EmptyInherit {
+			fields = append(fields, "Empty: "+emptyPolicyNames[column.Empty])
+		}
+		if column.Default != "" {
+			fields = append(fields, "Default: "+strconv.Quote(column.Default))
+		}
+		builder.WriteString("{" + strings.Join(fields, ", ") + "},\n")
+	}
+	builder.WriteString("},\n}")
+
+	return builder.String()
+}
+
+// typeName Get the name of the struct of a row.
+func (t *Table) typeName() string {
+	if t.TypeName != "" {
+		return t.TypeName
+	}
+
+	return toPascalCase(singular(t.Name))
+}
+
+// keyColumns Get the key columns of the table.
+func (t *Table) keyColumns() []string {
+	if t.Schema == nil || len(t.Schema.KeyColumns) == 0 {
+		return table.DefaultKeyColumns
+	}
+
+	return t.Schema.KeyColumns
+}
+
+// isIntKey Check if the rows of the table are identified by a single int column.
+func (t *Table) isIntKey() bool {
+	keyColumns := t.keyColumns()
+	return len(keyColumns) == 1 && t.fieldType(keyColumns[0]) == "int"
+}
+
+// column Get the definition of the column in the schema.
+func (t *Table) column(columnName string) (table.Column, bool) {
+	if t.Schema == nil {
+		return table.Column{}, false
+	}
+
+	return t.Schema.Column(columnName)
+}
+
+// fieldType Get the Go type of the field of the column.
+// A column that is not table.Schema.IsRequired is a pointer, since its empty policy or the null marker can leave a row without a value,
+// or store an empty value that a typed field cannot hold.
+func (t *Table) fieldType(columnName string) string {
+	column, ok := t.column(columnName)
+	if !ok {
+		if columnName == table.DefaultKeyColumns[0] {
+			return "int"
+		}
+		return "string"
+	}
+
+	var fieldType string
+	switch column.Type {
+	case table.TypeInt:
+		fieldType = "int"
+	case table.TypeFloat:
+		fieldType = "float64"
+	case table.TypeBool:
+		fieldType = "bool"
+	case table.TypeDate:
+		fieldType = "time.Time"
+	default:
+		fieldType = "string"
+	}
+	if !t.Schema.IsRequired(columnName) {
+		return "*" + fieldType
+	}
+
+	return fieldType
+}
+
+// fieldTag Get the `table` tag of the field of the column.
+// The layout is the last option, since it takes the rest of the tag and can contain commas.
+// A column name or a layout with a back quote cannot be written in the raw string of the tag, so it is an error.
+func (t *Table) fieldTag(columnName string) (string, error) {
+	tag := columnName
+	if column, ok := t.column(columnName); ok && column.Type == table.TypeDate && column.Layout != "" {
+		tag += ",layout=" + column.Layout
+	}
+	if strings.Contains(tag, "`") || strings.Contains(columnName, ",") {
+		return "", errors.New("Column cannot be written in a struct tag : " + tag)
+	}
+
+	return tag, nil
+}
+
+// usesTime Check if the struct of the table has a time field.
+func (t *Table) usesTime() bool {
+	for _, columnName := range t.Columns {
+		if strings.HasSuffix(t.fieldType(columnName), "time.Time") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toPascalCase Convert the name into pascal case. (ex. item_types -> ItemTypes, Long Sword -> LongSword)
+// A name starting with a digit gets the prefix X so that it is a valid identifier.
+func toPascalCase(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var builder strings.Builder
+	for _, word := range words {
+		runes := []rune(word)
+		builder.WriteRune(unicode.ToUpper(runes[0]))
+		builder.WriteString(string(runes[1:]))
+	}
+
+	r := builder.String()
+	if r != "" && unicode.IsDigit([]rune(r)[0]) {
+		return "X" + r
+	}
+
+	return r
+}
+
+// singular Convert the plural table name into singular in the simple English rules. (ex. item_types -> item_type)
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "ses"), strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "ss"), strings.HasSuffix(name, "us"):
+		return name
+	case strings.HasSuffix(name, "s"):
+		return strings.TrimSuffix(name, "s")
+	}
+
+	return name
+}
+
+// stringSliceLiteral Convert the values into the Go literal of a string slice.
+func stringSliceLiteral(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, strconv.Quote(value))
+	}
+
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+// writeFile Write the source to the file, creating the directory if it does not exist.
+func writeFile(path string, source []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, source, 0644)
+}
@@ -0,0 +1,89 @@
+package codegen
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stepupdream/go-support-tool/table"
+)
+
+func TestGenerate(t *testing.T) {
+	schema, err := table.LoadSchema("./testdata/schema/items.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	itemTypes, err := LoadTable("./testdata/item_types.csv", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	itemTypes.ConstantColumn = "name"
+	items, err := LoadTable("./testdata/items.csv", schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Generate("master", []*Table{itemTypes, items})
+	if err != nil {
+		t.Errorf("Generate() error = %v", err)
+		return
+	}
+	want, err := os.ReadFile("./testdata/tables_gen.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Generate() got = %s, want %s", got, want)
+	}
+
+	items.ConstantColumn = "name"
+	if _, err = Generate("master", []*Table{items}); err != nil {
+		t.Errorf("Generate() error = %v", err)
+	}
+	items.Rows[table.Key{Id: 2, Key: "name"}] = "Sword"
+	if _, err = Generate("master", []*Table{items}); err == nil {
+		t.Errorf("Generate() error = nil, want error of the same constant name")
+	}
+
+	items.ConstantColumn = ""
+	items.Schema = &table.Schema{Columns: []table.Column{{Name: "start_at", Type: table.TypeDate, Layout: "`2006`"}}}
+	if _, err = Generate("master", []*Table{items}); err == nil {
+		t.Errorf("Generate() error = nil, want error of the layout with a back quote")
+	}
+}
+
+func TestToPascalCase(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "item_types", want: "ItemTypes"},
+		{name: "long bow", want: "LongBow"},
+		{name: "1st_prize", want: "X1stPrize"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toPascalCase(tt.name); got != tt.want {
+				t.Errorf("toPascalCase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSingular(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "items", want: "item"},
+		{name: "gacha_boxes", want: "gacha_box"},
+		{name: "abilities", want: "ability"},
+		{name: "status", want: "status"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := singular(tt.name); got != tt.want {
+				t.Errorf("singular() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
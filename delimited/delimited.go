@@ -3,6 +3,7 @@ package delimited
 import (
 	"bufio"
 	"encoding/csv"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,10 +13,18 @@ import (
 
 // Load the specified file.
 func Load(targetPath string, isRowExclusion bool, isColumnExclusion bool) (rows [][]string, err error) {
+	rows, _, err = LoadWithLineNumbers(targetPath, isRowExclusion, isColumnExclusion)
+
+	return rows, err
+}
+
+// LoadWithLineNumbers Load the specified file, and return the line number in the file of each row together.
+// The line numbers start at 1 and take the excluded rows into account.
+func LoadWithLineNumbers(targetPath string, isRowExclusion bool, isColumnExclusion bool) (rows [][]string, lineNumbers []int, err error) {
 	extension := filepath.Ext(targetPath)
 	f, err := os.Open(targetPath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer func() {
 		closeErr := f.Close()
@@ -29,7 +38,7 @@ func Load(targetPath string, isRowExclusion bool, isColumnExclusion bool) (rows
 	ioReader := bufio.NewReader(f)
 	if hasBOM(ioReader) {
 		if _, err = ioReader.Discard(3); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -42,16 +51,24 @@ func Load(targetPath string, isRowExclusion bool, isColumnExclusion bool) (rows
 		csvReader.LazyQuotes = true
 	}
 
-	rows, err = csvReader.ReadAll()
-	if err != nil {
-		return nil, err
+	for {
+		row, readErr := csvReader.Read()
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, nil, readErr
+		}
+		lineNumber, _ := csvReader.FieldPos(0)
+		rows = append(rows, row)
+		lineNumbers = append(lineNumbers, lineNumber)
 	}
 
 	if isColumnExclusion {
 		rows = exclusionColumn(rows, isColumnExclusion)
 	}
 
-	return rows, nil
+	return rows, lineNumbers, nil
 }
 
 // hasBOM Check if the file has a BOM.
@@ -112,7 +129,7 @@ func CreateNewFile(path string, rows [][]string) (err error) {
 	writer := csv.NewWriter(separatedFile)
 	defer writer.Flush()
 
-	if filepath.Ext(path) == "tsv" {
+	if filepath.Ext(path) == ".tsv" {
 		writer.Comma = '\t'
 	}
 
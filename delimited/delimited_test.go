@@ -14,6 +14,7 @@ func TestMain(m *testing.M) {
 	// Remove the test file.
 	currentDir, _ := os.Getwd()
 	_ = os.RemoveAll(currentDir + "/testdata/sample10.csv")
+	_ = os.RemoveAll(currentDir + "/testdata/sample10.tsv")
 
 	os.Exit(code)
 }
@@ -24,9 +25,10 @@ func TestCreateNewFile(t *testing.T) {
 		rows [][]string
 	}
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name         string
+		args         args
+		expectedPath string
+		wantErr      bool
 	}{
 		{
 			name: "CreateNewFile1",
@@ -38,7 +40,21 @@ func TestCreateNewFile(t *testing.T) {
 					{"2", "bbb", "3", "43"},
 				},
 			},
-			wantErr: false,
+			expectedPath: "./testdata/sample.csv",
+			wantErr:      false,
+		},
+		{
+			name: "CreateNewFile2",
+			args: args{
+				path: "./testdata/sample10.tsv",
+				rows: [][]string{
+					{"id", "sample", "#", "level"},
+					{"#1", "ccc", "2", "13"},
+					{"2", "ddd", "3", "43"},
+				},
+			},
+			expectedPath: "./testdata/sample.tsv",
+			wantErr:      false,
 		},
 	}
 	for _, tt := range tests {
@@ -46,11 +62,11 @@ func TestCreateNewFile(t *testing.T) {
 			if err := CreateNewFile(tt.args.path, tt.args.rows); (err != nil) != tt.wantErr {
 				t.Errorf("CreateNewFile() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			expected, err := os.ReadFile("./testdata/sample.csv")
+			expected, err := os.ReadFile(tt.expectedPath)
 			if err != nil {
 				t.Fatal(err)
 			}
-			actual, err := os.ReadFile("./testdata/sample10.csv")
+			actual, err := os.ReadFile(tt.args.path)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -143,3 +159,21 @@ func TestLoad(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadWithLineNumbers(t *testing.T) {
+	rows, lineNumbers, err := LoadWithLineNumbers("./testdata/sample.csv", true, true)
+	if err != nil {
+		t.Errorf("LoadWithLineNumbers() error = %v", err)
+		return
+	}
+	wantRows := [][]string{
+		{"id", "sample", "level"},
+		{"2", "bbb", "43"},
+	}
+	if !reflect.DeepEqual(rows, wantRows) {
+		t.Errorf("LoadWithLineNumbers() rows = %v, want %v", rows, wantRows)
+	}
+	if !reflect.DeepEqual(lineNumbers, []int{1, 3}) {
+		t.Errorf("LoadWithLineNumbers() lineNumbers = %v, want %v", lineNumbers, []int{1, 3})
+	}
+}
@@ -0,0 +1,556 @@
+package table
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+// BinaryMagic is the first bytes of the binary format of the tables.
+const BinaryMagic = "MDBN"
+
+// BinaryVersion is the version of the binary format written by WriteBinary.
+const BinaryVersion uint16 = 1
+
+// binaryIndexEntrySize is the size of an entry of the id index. (id int64, code uint32, offset uint32)
+const binaryIndexEntrySize = 16
+
+// binaryEncoding is how the values of a column are stored.
+type binaryEncoding byte
+
+const (
+	encodingString binaryEncoding = iota
+	encodingInt
+	encodingFloat
+	encodingBool
+)
+
+// WriteBinary Write the tables to the writer in the binary format.
+//
+// The format is little endian and has the following layout. A string is stored once in the string table,
+// and the other parts refer to it by its index. Each column is stored in the most compact type (int, float or bool)
+// that reproduces all of its values, and in the string table otherwise.
+//
+//	magic "MDBN", version uint16
+//	string count uvarint, (length uvarint, bytes) * count
+//	table count uvarint, and for each table:
+//	  name uvarint, column count uvarint, (name uvarint, encoding byte) * count, key column count uvarint, (name uvarint) * count
+//	  row count uvarint, (id int64, code uint32, offset uint32) * count in the order of the row id
+//	  data length uvarint, and for each row: the bitmap of the columns that have a value, and the values
+//
+//goland:noinspection GoUnusedExportedFunction
+func WriteBinary(writer io.Writer, tables ...*MasterData) error {
+	interner := newStringInterner()
+
+	var tableSection []byte
+	tableSection = binary.AppendUvarint(tableSection, uint64(len(tables)))
+	for _, m := range tables {
+		var err error
+		if tableSection, err = m.appendBinary(tableSection, interner); err != nil {
+			return errors.Wrap(err, "table : "+m.name)
+		}
+	}
+
+	var header []byte
+	header = append(header, BinaryMagic...)
+	header = binary.LittleEndian.AppendUint16(header, BinaryVersion)
+	header = binary.AppendUvarint(header, uint64(len(interner.values)))
+	for _, value := range interner.values {
+		header = binary.AppendUvarint(header, uint64(len(value)))
+		header = append(header, value...)
+	}
+
+	if _, err := writer.Write(header); err != nil {
+		return err
+	}
+	_, err := writer.Write(tableSection)
+
+	return err
+}
+
+// ExportBinary Write the tables to the specified file in the binary format.
+//
+//goland:noinspection GoUnusedExportedFunction
+func ExportBinary(path string, tables ...*MasterData) error {
+	return exportFile(path, func(writer io.Writer) error {
+		return WriteBinary(writer, tables...)
+	})
+}
+
+// appendBinary Append the table in the binary format.
+func (m *MasterData) appendBinary(buffer []byte, interner *stringInterner) ([]byte, error) {
+	columns := m.Columns()
+	ids := m.RowIds()
+
+	encodings := make([]binaryEncoding, len(columns))
+	buffer = binary.AppendUvarint(buffer, uint64(interner.intern(m.name)))
+	buffer = binary.AppendUvarint(buffer, uint64(len(columns)))
+	for i, column := range columns {
+		encodings[i] = m.binaryEncoding(ids, column)
+		buffer = binary.AppendUvarint(buffer, uint64(interner.intern(column)))
+		buffer = append(buffer, byte(encodings[i]))
+	}
+	keyColumns := m.schema.keyColumns()
+	buffer = binary.AppendUvarint(buffer, uint64(len(keyColumns)))
+	for _, column := range keyColumns {
+		buffer = binary.AppendUvarint(buffer, uint64(interner.intern(column)))
+	}
+
+	var data []byte
+	index := make([]byte, 0, len(ids)*binaryIndexEntrySize)
+	bitmapSize := (len(columns) + 7) / 8
+	for _, id := range ids {
+		if len(data) > math.MaxUint32 {
+			return nil, errors.New("Table is too large for the binary format")
+		}
+		index = binary.LittleEndian.AppendUint64(index, uint64(id.Id))
+		index = binary.LittleEndian.AppendUint32(index, interner.intern(id.Code))
+		index = binary.LittleEndian.AppendUint32(index, uint32(len(data)))
+
+		bitmapStart := len(data)
+		data = append(data, make([]byte, bitmapSize)...)
+		for i, column := range columns {
+			value, ok := m.Rows[id.Key(column)]
+			if !ok {
+				continue
+			}
+			data[bitmapStart+i/8] |= 1 << (i % 8)
+			data = appendBinaryValue(data, encodings[i], value, interner)
+		}
+	}
+
+	buffer = binary.AppendUvarint(buffer, uint64(len(ids)))
+	buffer = append(buffer, index...)
+	buffer = binary.AppendUvarint(buffer, uint64(len(data)))
+
+	return append(buffer, data...), nil
+}
+
+// binaryEncoding Get the most compact encoding that reproduces all values of the column.
+func (m *MasterData) binaryEncoding(ids []RowId, column string) binaryEncoding {
+	for _, encoding := range []binaryEncoding{encodingInt, encodingFloat, encodingBool} {
+		found, matched := false, true
+		for _, id := range ids {
+			value, ok := m.Rows[id.Key(column)]
+			if !ok {
+				continue
+			}
+			found = true
+			if !isCanonical(encoding, value) {
+				matched = false
+				break
+			}
+		}
+		if found && matched {
+			return encoding
+		}
+	}
+
+	return encodingString
+}
+
+// isCanonical Check if the value is restored as the same text from the encoding.
+func isCanonical(encoding binaryEncoding, value string) bool {
+	switch encoding {
+	case encodingInt:
+		number, err := strconv.ParseInt(value, 10, 64)
+		return err == nil && strconv.FormatInt(number, 10) == value
+	case encodingFloat:
+		number, err := strconv.ParseFloat(value, 64)
+		return err == nil && strconv.FormatFloat(number, 'f', -1, 64) == value
+	case encodingBool:
+		return value == "true" || value == "false"
+	}
+
+	return true
+}
+
+// appendBinaryValue Append the value in the encoding.
+func appendBinaryValue(data []byte, encoding binaryEncoding, value string, interner *stringInterner) []byte {
+	switch encoding {
+	case encodingInt:
+		number, _ := strconv.ParseInt(value, 10, 64)
+		return binary.AppendVarint(data, number)
+	case encodingFloat:
+		number, _ := strconv.ParseFloat(value, 64)
+		return binary.LittleEndian.AppendUint64(data, math.Float64bits(number))
+	case encodingBool:
+		if value == "true" {
+			return append(data, 1)
+		}
+		return append(data, 0)
+	}
+
+	return binary.AppendUvarint(data, uint64(interner.intern(value)))
+}
+
+// stringInterner stores each string once and gives it an index. The empty string is always index 0.
+type stringInterner struct {
+	indexes map[string]uint32
+	values  []string
+}
+
+func newStringInterner() *stringInterner {
+	return &stringInterner{indexes: map[string]uint32{"": 0}, values: []string{""}}
+}
+
+// intern Get the index of the string, adding it if it is new.
+func (s *stringInterner) intern(value string) uint32 {
+	if index, ok := s.indexes[value]; ok {
+		return index
+	}
+
+	index := uint32(len(s.values))
+	s.indexes[value] = index
+	s.values = append(s.values, value)
+
+	return index
+}
+
+// BinaryReader reads the tables of the binary format without copying the data.
+// The strings it returns share the memory of the data, so the data must not be changed while they are used.
+type BinaryReader struct {
+	strings []string
+	tables  []*BinaryTable
+}
+
+// BinaryTable is a table of the binary format.
+type BinaryTable struct {
+	name       string
+	columns    []string
+	keyColumns []string
+	encodings  []binaryEncoding
+	rowCount   int
+	index      []byte
+	data       []byte
+	strings    []string
+}
+
+// NewBinaryReader Create a reader of the data in the binary format. The whole data is checked.
+//
+//goland:noinspection GoUnusedExportedFunction
+func NewBinaryReader(data []byte) (*BinaryReader, error) {
+	if !bytes.HasPrefix(data, []byte(BinaryMagic)) || len(data) < len(BinaryMagic)+2 {
+		return nil, errors.New("Not the binary format of the tables")
+	}
+	version := binary.LittleEndian.Uint16(data[len(BinaryMagic):])
+	if version != BinaryVersion {
+		return nil, errors.New("Unsupported binary version : " + strconv.Itoa(int(version)))
+	}
+
+	d := &binaryDecoder{data: data, offset: len(BinaryMagic) + 2}
+	r := &BinaryReader{}
+	stringCount := d.count(1)
+	r.strings = make([]string, 0, stringCount)
+	for i := 0; i < stringCount; i++ {
+		r.strings = append(r.strings, d.string())
+	}
+
+	tableCount := d.count(1)
+	for i := 0; i < tableCount && d.err == nil; i++ {
+		t := &BinaryTable{strings: r.strings}
+		t.name = d.stringIndex(r.strings)
+		columnCount := d.count(2)
+		for j := 0; j < columnCount && d.err == nil; j++ {
+			t.columns = append(t.columns, d.stringIndex(r.strings))
+			t.encodings = append(t.encodings, binaryEncoding(d.bytes(1)[0]))
+		}
+		keyColumnCount := d.count(1)
+		for j := 0; j < keyColumnCount && d.err == nil; j++ {
+			t.keyColumns = append(t.keyColumns, d.stringIndex(r.strings))
+		}
+		t.rowCount = d.count(binaryIndexEntrySize)
+		t.index = d.bytes(t.rowCount * binaryIndexEntrySize)
+		t.data = d.bytes(d.count(1))
+		if d.err == nil {
+			d.err = t.check()
+		}
+		r.tables = append(r.tables, t)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return r, nil
+}
+
+// OpenBinary Read the file in the binary format.
+//
+//goland:noinspection GoUnusedExportedFunction
+func OpenBinary(path string) (*BinaryReader, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBinaryReader(data)
+}
+
+// Names Get the names of the tables in the order they were written.
+func (r *BinaryReader) Names() []string {
+	names := make([]string, 0, len(r.tables))
+	for _, t := range r.tables {
+		names = append(names, t.name)
+	}
+
+	return names
+}
+
+// Table Get the table of the specified name.
+func (r *BinaryReader) Table(name string) (*BinaryTable, bool) {
+	for _, t := range r.tables {
+		if t.name == name {
+			return t, true
+		}
+	}
+
+	return nil, false
+}
+
+// Name Get the name of the table.
+func (t *BinaryTable) Name() string {
+	return t.name
+}
+
+// Columns Get the columns of the table in order.
+func (t *BinaryTable) Columns() []string {
+	return t.columns
+}
+
+// KeyColumns Get the key columns of the table.
+func (t *BinaryTable) KeyColumns() []string {
+	return t.keyColumns
+}
+
+// Len Get the number of rows of the table.
+func (t *BinaryTable) Len() int {
+	return t.rowCount
+}
+
+// RowIds Get the ids of all rows in ascending order.
+func (t *BinaryTable) RowIds() []RowId {
+	r := make([]RowId, 0, t.rowCount)
+	for i := 0; i < t.rowCount; i++ {
+		r = append(r, t.rowId(i))
+	}
+
+	return r
+}
+
+// Value Get the value of the column of the row. The row is found by binary search on the id index.
+func (t *BinaryTable) Value(id RowId, column string) (string, bool) {
+	columnNumber := -1
+	for i, name := range t.columns {
+		if name == column {
+			columnNumber = i
+			break
+		}
+	}
+	if columnNumber < 0 {
+		return "", false
+	}
+
+	i := sort.Search(t.rowCount, func(i int) bool {
+		return !t.rowId(i).Less(id)
+	})
+	if i == t.rowCount || t.rowId(i) != id {
+		return "", false
+	}
+
+	var value string
+	found := false
+	t.eachValue(i, func(columnNumber2 int, v string) {
+		if columnNumber2 == columnNumber {
+			value, found = v, true
+		}
+	})
+
+	return value, found
+}
+
+// Map Convert the table into the map of the values.
+func (t *BinaryTable) Map() map[Key]string {
+	r := make(map[Key]string)
+	for i := 0; i < t.rowCount; i++ {
+		id := t.rowId(i)
+		t.eachValue(i, func(columnNumber int, value string) {
+			r[id.Key(t.columns[columnNumber])] = value
+		})
+	}
+
+	return r
+}
+
+// MasterData Convert the table into MasterData with the same columns and key columns.
+func (t *BinaryTable) MasterData(extensionName string) *MasterData {
+	m := NewTabular(t.name, extensionName, t.Map(), false)
+	m.SetColumns(append([]string{}, t.columns...))
+	m.SetSchema(&Schema{KeyColumns: append([]string{}, t.keyColumns...)})
+
+	return m
+}
+
+// rowId Get the row id of the entry of the index.
+func (t *BinaryTable) rowId(i int) RowId {
+	entry := t.index[i*binaryIndexEntrySize:]
+	return RowId{
+		Id:   int(int64(binary.LittleEndian.Uint64(entry))),
+		Code: t.strings[binary.LittleEndian.Uint32(entry[8:])],
+	}
+}
+
+// eachValue Call the function with each value of the row.
+func (t *BinaryTable) eachValue(i int, function func(columnNumber int, value string)) {
+	d := &binaryDecoder{data: t.data, offset: int(binary.LittleEndian.Uint32(t.index[i*binaryIndexEntrySize+12:]))}
+	bitmap := d.bytes((len(t.columns) + 7) / 8)
+	for columnNumber := range t.columns {
+		if d.err != nil || bitmap[columnNumber/8]&(1<<(columnNumber%8)) == 0 {
+			continue
+		}
+		value := d.value(t.encodings[columnNumber], t.strings)
+		if d.err == nil {
+			function(columnNumber, value)
+		}
+	}
+}
+
+// check Check that the index and the values of the table are within the data.
+func (t *BinaryTable) check() error {
+	for _, encoding := range t.encodings {
+		if encoding > encodingBool {
+			return errors.New("Invalid binary : unknown encoding of table : " + t.name)
+		}
+	}
+
+	for i := 0; i < t.rowCount; i++ {
+		entry := t.index[i*binaryIndexEntrySize:]
+		if int(binary.LittleEndian.Uint32(entry[8:])) >= len(t.strings) || int(binary.LittleEndian.Uint32(entry[12:])) > len(t.data) {
+			return errors.New("Invalid binary : id index of table : " + t.name)
+		}
+		// The writer sorts the ids by RowId.Less, which is a strict total order, so equal neighbors are also invalid.
+		if i > 0 && !t.rowId(i-1).Less(t.rowId(i)) {
+			return errors.New("Invalid binary : id index is not sorted of table : " + t.name)
+		}
+
+		d := &binaryDecoder{data: t.data, offset: int(binary.LittleEndian.Uint32(entry[12:]))}
+		bitmap := d.bytes((len(t.columns) + 7) / 8)
+		for columnNumber := range t.columns {
+			if d.err == nil && bitmap[columnNumber/8]&(1<<(columnNumber%8)) != 0 {
+				d.value(t.encodings[columnNumber], t.strings)
+			}
+		}
+		if d.err != nil {
+			return errors.Wrap(d.err, "table : "+t.name)
+		}
+	}
+
+	return nil
+}
+
+// binaryDecoder reads the data in order. After an error, it reads nothing and keeps the first error.
+type binaryDecoder struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+// bytes Read the bytes of the length without copying.
+func (d *binaryDecoder) bytes(length int) []byte {
+	if length < 0 {
+		length = 0
+	}
+	if d.err != nil || d.offset+length > len(d.data) {
+		d.fail()
+		// Return zeros so that the caller can read the length before it checks the error.
+		return make([]byte, length)
+	}
+	r := d.data[d.offset : d.offset+length]
+	d.offset += length
+
+	return r
+}
+
+// uvarint Read an unsigned varint.
+func (d *binaryDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	r, n := binary.Uvarint(d.data[d.offset:])
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.offset += n
+
+	return r
+}
+
+// count Read the number of the items whose size is at least itemSize, checking that they can be in the rest of the data.
+func (d *binaryDecoder) count(itemSize int) int {
+	r := d.uvarint()
+	if r > uint64(len(d.data)-d.offset)/uint64(itemSize) {
+		d.fail()
+		return 0
+	}
+
+	return int(r)
+}
+
+// string Read a string that shares the memory of the data.
+func (d *binaryDecoder) string() string {
+	b := d.bytes(d.count(1))
+	if len(b) == 0 {
+		return ""
+	}
+
+	return unsafe.String(&b[0], len(b))
+}
+
+// stringIndex Read the index of a string and get the string.
+func (d *binaryDecoder) stringIndex(strings []string) string {
+	index := d.uvarint()
+	if index >= uint64(len(strings)) {
+		d.fail()
+		return ""
+	}
+
+	return strings[index]
+}
+
+// value Read a value of the encoding as text.
+func (d *binaryDecoder) value(encoding binaryEncoding, strings []string) string {
+	switch encoding {
+	case encodingInt:
+		if d.err != nil {
+			return ""
+		}
+		r, n := binary.Varint(d.data[d.offset:])
+		if n <= 0 {
+			d.fail()
+			return ""
+		}
+		d.offset += n
+		return strconv.FormatInt(r, 10)
+	case encodingFloat:
+		b := d.bytes(8)
+		return strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(b)), 'f', -1, 64)
+	case encodingBool:
+		return strconv.FormatBool(d.bytes(1)[0] == 1)
+	}
+
+	return d.stringIndex(strings)
+}
+
+// fail Record that the data is broken.
+func (d *binaryDecoder) fail() {
+	if d.err == nil {
+		d.err = errors.New("Invalid binary : unexpected end of data at offset : " + strconv.Itoa(d.offset))
+	}
+}
@@ -0,0 +1,142 @@
+package table
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func testBinaryTables() []*MasterData {
+	items := NewTabular("items", "csv", map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "sword", {Id: 1, Key: "rate"}: "1.5", {Id: 1, Key: "is_limited"}: "true", {Id: 1, Key: "code"}: "01",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "", {Id: 2, Key: "rate"}: "-3", {Id: 2, Key: "code"}: "2",
+		{Id: -5, Key: "id"}: "-5", {Id: -5, Key: "name"}: "sword", {Id: -5, Key: "is_limited"}: "false",
+	}, false)
+	questSteps := NewTabular("quest_steps", "csv", map[Key]string{
+		{Code: "1:10", Key: "quest_id"}: "1", {Code: "1:10", Key: "step"}: "10", {Code: "1:10", Key: "memo"}: "it's",
+		{Code: "1:2", Key: "quest_id"}: "1", {Code: "1:2", Key: "step"}: "2",
+	}, false)
+	questSteps.SetSchema(&Schema{KeyColumns: []string{"quest_id", "step"}})
+
+	return []*MasterData{items, questSteps}
+}
+
+func TestWriteBinary(t *testing.T) {
+	tables := testBinaryTables()
+	var buffer bytes.Buffer
+	if err := WriteBinary(&buffer, tables...); err != nil {
+		t.Errorf("WriteBinary() error = %v", err)
+		return
+	}
+
+	r, err := NewBinaryReader(buffer.Bytes())
+	if err != nil {
+		t.Errorf("NewBinaryReader() error = %v", err)
+		return
+	}
+	if got, want := r.Names(), []string{"items", "quest_steps"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	for _, m := range tables {
+		binaryTable, ok := r.Table(m.name)
+		if !ok {
+			t.Errorf("Table() %v not found", m.name)
+			continue
+		}
+		if got := binaryTable.Map(); !reflect.DeepEqual(got, m.Rows) {
+			t.Errorf("Map() = %v, want %v", got, m.Rows)
+		}
+		if got, want := binaryTable.RowIds(), m.RowIds(); !reflect.DeepEqual(got, want) {
+			t.Errorf("RowIds() = %v, want %v", got, want)
+		}
+		if got, want := binaryTable.MasterData("csv").ToRows(), m.ToRows(); !reflect.DeepEqual(got, want) {
+			t.Errorf("MasterData() = %v, want %v", got, want)
+		}
+	}
+
+	items, _ := r.Table("items")
+	tests := []struct {
+		id     RowId
+		column string
+		want   string
+		wantOk bool
+	}{
+		{id: RowId{Id: 1}, column: "code", want: "01", wantOk: true},
+		{id: RowId{Id: 2}, column: "rate", want: "-3", wantOk: true},
+		{id: RowId{Id: -5}, column: "is_limited", want: "false", wantOk: true},
+		{id: RowId{Id: -5}, column: "rate", want: "", wantOk: false},
+		{id: RowId{Id: 3}, column: "id", want: "", wantOk: false},
+	}
+	for _, tt := range tests {
+		if got, ok := items.Value(tt.id, tt.column); got != tt.want || ok != tt.wantOk {
+			t.Errorf("Value(%v, %v) = %v %v, want %v %v", tt.id, tt.column, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestWriteBinary_EquivalentCodes(t *testing.T) {
+	rows := make(map[Key]string)
+	for _, code := range []string{"01", "1", "001", "a", "x:y"} {
+		rows[codeRowId(code).Key("code")] = code
+		rows[codeRowId(code).Key("name")] = "name " + code
+	}
+	m := NewTabular("items", "csv", rows, false)
+	m.SetSchema(&Schema{KeyColumns: []string{"code"}})
+
+	var buffer bytes.Buffer
+	if err := WriteBinary(&buffer, m); err != nil {
+		t.Errorf("WriteBinary() error = %v", err)
+		return
+	}
+	r, err := NewBinaryReader(buffer.Bytes())
+	if err != nil {
+		t.Errorf("NewBinaryReader() error = %v", err)
+		return
+	}
+	items, _ := r.Table("items")
+	if got := items.Map(); !reflect.DeepEqual(got, m.Rows) {
+		t.Errorf("Map() = %v, want %v", got, m.Rows)
+	}
+	for _, code := range []string{"01", "1", "001", "a", "x:y"} {
+		if got, ok := items.Value(codeRowId(code), "name"); got != "name "+code || !ok {
+			t.Errorf("Value(%v) = %v %v, want %v true", code, got, ok, "name "+code)
+		}
+	}
+}
+
+func TestNewBinaryReader_Error(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WriteBinary(&buffer, testBinaryTables()...); err != nil {
+		t.Fatal(err)
+	}
+	data := buffer.Bytes()
+
+	for length := 0; length < len(data); length++ {
+		if _, err := NewBinaryReader(data[:length]); err == nil {
+			t.Errorf("NewBinaryReader() of %v bytes error = nil, want error", length)
+		}
+	}
+
+	changed := append([]byte{}, data...)
+	changed[len(BinaryMagic)] = 2
+	if _, err := NewBinaryReader(changed); err == nil {
+		t.Errorf("NewBinaryReader() of version 2 error = nil, want error")
+	}
+}
+
+func TestExportBinary(t *testing.T) {
+	path := t.TempDir() + string(os.PathSeparator) + "master.bin"
+	if err := ExportBinary(path, testBinaryTables()...); err != nil {
+		t.Errorf("ExportBinary() error = %v", err)
+		return
+	}
+	r, err := OpenBinary(path)
+	if err != nil {
+		t.Errorf("OpenBinary() error = %v", err)
+		return
+	}
+	if items, ok := r.Table("items"); !ok || items.Len() != 3 {
+		t.Errorf("OpenBinary() got = %v", r.Names())
+	}
+}
@@ -0,0 +1,341 @@
+package table
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// SliceSeparator is the separator used for slice fields when no separator is specified in the tag.
+const SliceSeparator = "|"
+
+// fieldTag is the parsed `table` struct tag of a field.
+// ex. `table:"tags,split=;"` `table:"start_at,layout=2006-01-02"` `table:"-"`
+// The layout option takes the rest of the tag, so that the layout can contain commas. (ex. `table:"start_at,layout=Jan 2, 2006"`)
+type fieldTag struct {
+	index     int
+	column    string
+	separator string
+	layout    string
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Decode Convert the rows of MasterData into structs, ordered by the row id.
+// Columns are mapped to fields by the `table` struct tag, or by the snake case of the field name if there is no tag.
+// The layout of a time field is taken from the tag, the schema of the MasterData, or DateLayout in that order.
+// Only the rows that exist by HasRow are converted.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Decode[T any](m *MasterData) ([]T, error) {
+	return decode[T](m.RowIds(), m.Rows, m.schema)
+}
+
+// DecodeMap Convert the map into structs, ordered by the row id.
+// Every row id in the map is a row, as in a loaded file.
+//
+//goland:noinspection GoUnusedExportedFunction
+func DecodeMap[T any](valueMap map[Key]string) ([]T, error) {
+	return decode[T](PluckRowId(valueMap), valueMap, nil)
+}
+
+// Encode Convert the structs into a map. The struct must have an int field mapped to the id column.
+// Nil pointer fields are not stored in the map.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Encode[T any](values []T) (map[Key]string, error) {
+	return EncodeWithSchema(values, nil)
+}
+
+// EncodeWithSchema Convert the structs into a map, identifying the rows by the key columns of the schema.
+// The struct must have a field mapped to each key column. If the schema is nil, DefaultKeyColumns is used.
+// The layout of a time field is taken from the tag, the schema, or DateLayout in that order.
+//
+//goland:noinspection GoUnusedExportedFunction
+func EncodeWithSchema[T any](values []T, schema *Schema) (map[Key]string, error) {
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+	tags, err := parseFieldTags(structType, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	keyTags := make([]fieldTag, 0, len(schema.keyColumns()))
+	for _, keyColumn := range schema.keyColumns() {
+		found := false
+		for _, tag := range tags {
+			if tag.column == keyColumn {
+				keyTags = append(keyTags, tag)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.New("Not found field for key column : " + keyColumn + " " + structType.String())
+		}
+	}
+
+	r := make(map[Key]string)
+	for _, value := range values {
+		structValue := reflect.ValueOf(value)
+		id, err := encodeRowId(structValue, keyTags, schema)
+		if err != nil {
+			return nil, errors.Wrap(err, structType.String())
+		}
+		for _, tag := range tags {
+			text, ok := encodeValue(structValue.Field(tag.index), tag)
+			if !ok {
+				continue
+			}
+			if _, exists := r[id.Key(tag.column)]; exists {
+				return nil, errors.New("Duplicate key : id " + id.String() + " " + structType.String())
+			}
+			r[id.Key(tag.column)] = text
+		}
+	}
+
+	return r, nil
+}
+
+// encodeRowId Get the id of the row of the struct from the fields of the key columns.
+func encodeRowId(structValue reflect.Value, keyTags []fieldTag, schema *Schema) (RowId, error) {
+	values := make([]string, 0, len(keyTags))
+	for _, tag := range keyTags {
+		text, ok := encodeValue(structValue.Field(tag.index), tag)
+		if !ok || text == "" {
+			return RowId{}, errors.New("Empty key : " + tag.column)
+		}
+		values = append(values, text)
+	}
+
+	if schema.isNumericKey() {
+		id, err := strconv.Atoi(values[0])
+		if err != nil {
+			return RowId{}, errors.New("ID is not numeric : " + values[0])
+		}
+		return RowId{Id: id}, nil
+	}
+
+	return codeRowId(values...), nil
+}
+
+// decode Convert the rows of the ids into structs. The values of the rows are read from the map.
+func decode[T any](ids []RowId, valueMap map[Key]string, schema *Schema) ([]T, error) {
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+	tags, err := parseFieldTags(structType, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]T, 0, len(ids))
+	for _, id := range ids {
+		var value T
+		structValue := reflect.ValueOf(&value).Elem()
+		for _, tag := range tags {
+			text, ok := valueMap[id.Key(tag.column)]
+			field := structValue.Field(tag.index)
+			if !ok && field.Kind() != reflect.Pointer {
+				return nil, errors.New("Not found column : id " + id.String() + " column " + tag.column)
+			}
+			if !ok {
+				continue
+			}
+			if err = decodeValue(field, text, tag); err != nil {
+				return nil, errors.Wrap(err, "Failed to decode : id "+id.String()+" column "+tag.column)
+			}
+		}
+		r = append(r, value)
+	}
+
+	return r, nil
+}
+
+// parseFieldTags Parse the `table` tags of the exported fields of the struct.
+func parseFieldTags(structType reflect.Type, schema *Schema) ([]fieldTag, error) {
+	if structType.Kind() != reflect.Struct {
+		return nil, errors.New("Not a struct type : " + structType.String())
+	}
+
+	var tags []fieldTag
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tagText := field.Tag.Get("table")
+		if !field.IsExported() || tagText == "-" {
+			continue
+		}
+
+		if !isSupportedType(field.Type) {
+			return nil, errors.New("Unsupported field type : " + structType.String() + "." + field.Name)
+		}
+
+		options := strings.Split(tagText, ",")
+		tag := fieldTag{index: i, column: options[0], separator: SliceSeparator}
+		if tag.column == "" {
+			tag.column = toSnakeCase(field.Name)
+		}
+		for j, option := range options[1:] {
+			optionName, optionValue, _ := strings.Cut(option, "=")
+			if optionName == "layout" {
+				tag.layout = strings.Join(append([]string{optionValue}, options[j+2:]...), ",")
+				break
+			}
+			switch optionName {
+			case "split":
+				tag.separator = optionValue
+			default:
+				return nil, errors.New("Unknown tag option : " + option + " " + structType.String() + "." + field.Name)
+			}
+		}
+		if tag.layout == "" && schema != nil {
+			if column, ok := schema.Column(tag.column); ok {
+				tag.layout = column.Layout
+			}
+		}
+		if tag.layout == "" {
+			tag.layout = DateLayout
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// decodeValue Set the text to the field according to the type of the field.
+func decodeValue(field reflect.Value, text string, tag fieldTag) error {
+	switch {
+	case field.Kind() == reflect.Pointer:
+		if text == "" {
+			return nil
+		}
+		pointer := reflect.New(field.Type().Elem())
+		if err := decodeValue(pointer.Elem(), text, tag); err != nil {
+			return err
+		}
+		field.Set(pointer)
+	case field.Type() == timeType:
+		parsed, err := time.Parse(tag.layout, text)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(parsed))
+	case field.Kind() == reflect.Slice:
+		var elements []string
+		if text != "" {
+			elements = strings.Split(text, tag.separator)
+		}
+		slice := reflect.MakeSlice(field.Type(), len(elements), len(elements))
+		for i, element := range elements {
+			if err := decodeValue(slice.Index(i), element, tag); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+	case field.Kind() == reflect.String:
+		field.SetString(text)
+	case field.Kind() == reflect.Bool:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case isIntKind(field.Kind()):
+		parsed, err := strconv.ParseInt(text, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case isUintKind(field.Kind()):
+		parsed, err := strconv.ParseUint(text, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(parsed)
+	case field.Kind() == reflect.Float32 || field.Kind() == reflect.Float64:
+		parsed, err := strconv.ParseFloat(text, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	default:
+		return errors.New("Unsupported field type : " + field.Type().String())
+	}
+
+	return nil
+}
+
+// encodeValue Convert the field into text. If the field is a nil pointer, return false.
+func encodeValue(field reflect.Value, tag fieldTag) (string, bool) {
+	switch {
+	case field.Kind() == reflect.Pointer:
+		if field.IsNil() {
+			return "", false
+		}
+		return encodeValue(field.Elem(), tag)
+	case field.Type() == timeType:
+		return field.Interface().(time.Time).Format(tag.layout), true
+	case field.Kind() == reflect.Slice:
+		elements := make([]string, 0, field.Len())
+		for i := 0; i < field.Len(); i++ {
+			element, _ := encodeValue(field.Index(i), tag)
+			elements = append(elements, element)
+		}
+		return strings.Join(elements, tag.separator), true
+	case field.Kind() == reflect.String:
+		return field.String(), true
+	case field.Kind() == reflect.Bool:
+		return strconv.FormatBool(field.Bool()), true
+	case isIntKind(field.Kind()):
+		return strconv.FormatInt(field.Int(), 10), true
+	case isUintKind(field.Kind()):
+		return strconv.FormatUint(field.Uint(), 10), true
+	case field.Kind() == reflect.Float32 || field.Kind() == reflect.Float64:
+		return strconv.FormatFloat(field.Float(), 'f', -1, field.Type().Bits()), true
+	}
+
+	return "", false
+}
+
+// isSupportedType Check if the type can be converted from and to text.
+func isSupportedType(fieldType reflect.Type) bool {
+	switch {
+	case fieldType.Kind() == reflect.Pointer:
+		return fieldType.Elem().Kind() != reflect.Slice && isSupportedType(fieldType.Elem())
+	case fieldType.Kind() == reflect.Slice:
+		return fieldType.Elem().Kind() != reflect.Pointer && fieldType.Elem().Kind() != reflect.Slice && isSupportedType(fieldType.Elem())
+	case fieldType == timeType:
+		return true
+	}
+
+	kind := fieldType.Kind()
+
+	return kind == reflect.String || kind == reflect.Bool || isIntKind(kind) || isUintKind(kind) || kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+func isUintKind(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uint64
+}
+
+// toSnakeCase Convert the field name into snake case. (ex. ItemID -> item_id)
+func toSnakeCase(fieldName string) string {
+	runes := []rune(fieldName)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			isPreviousLower := !unicode.IsUpper(runes[i-1])
+			isNextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if isPreviousLower || isNextLower {
+				builder.WriteRune('_')
+			}
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,223 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testItem struct {
+	Id        int
+	Name      string
+	Rate      *float64
+	IsLimited bool      `table:"limited"`
+	StartAt   time.Time `table:"start_at,layout=2006-01-02"`
+	TagIds    []uint8   `table:",split=;"`
+	Memo      string    `table:"-"`
+}
+
+type testEvent struct {
+	Id    int
+	EndAt time.Time `table:"end_at,layout=Jan 2, 2006"`
+}
+
+func TestDecodeMap_LayoutWithComma(t *testing.T) {
+	got, err := DecodeMap[testEvent](map[Key]string{
+		{Id: 1, Key: "id"}:     "1",
+		{Id: 1, Key: "end_at"}: "Apr 30, 2023",
+	})
+	if err != nil {
+		t.Errorf("DecodeMap() error = %v", err)
+		return
+	}
+	want := []testEvent{{Id: 1, EndAt: time.Date(2023, 4, 30, 0, 0, 0, 0, time.UTC)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeMap() got = %v, want %v", got, want)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	rate := 0.5
+	tests := []struct {
+		name    string
+		rows    map[Key]string
+		want    []testItem
+		wantErr bool
+	}{
+		{
+			name: "Decode1",
+			rows: map[Key]string{
+				{Id: 2, Key: "id"}:       "2",
+				{Id: 2, Key: "name"}:     "shield",
+				{Id: 2, Key: "limited"}:  "false",
+				{Id: 2, Key: "start_at"}: "2023-02-01",
+				{Id: 2, Key: "tag_ids"}:  "",
+				{Id: 1, Key: "id"}:       "1",
+				{Id: 1, Key: "name"}:     "sword",
+				{Id: 1, Key: "rate"}:     "0.5",
+				{Id: 1, Key: "limited"}:  "true",
+				{Id: 1, Key: "start_at"}: "2023-01-01",
+				{Id: 1, Key: "tag_ids"}:  "1;2",
+			},
+			want: []testItem{
+				{Id: 1, Name: "sword", Rate: &rate, IsLimited: true, StartAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), TagIds: []uint8{1, 2}},
+				{Id: 2, Name: "shield", IsLimited: false, StartAt: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), TagIds: []uint8{}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Decode2",
+			rows: map[Key]string{
+				{Id: 1, Key: "id"}:       "1",
+				{Id: 1, Key: "name"}:     "sword",
+				{Id: 1, Key: "limited"}:  "yes",
+				{Id: 1, Key: "start_at"}: "2023-01-01",
+				{Id: 1, Key: "tag_ids"}:  "1",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Decode3",
+			rows: map[Key]string{
+				{Id: 1, Key: "id"}:      "1",
+				{Id: 1, Key: "name"}:    "sword",
+				{Id: 1, Key: "limited"}: "true",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode[testItem](NewTabular("items", "csv", tt.rows, false))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	rate := 0.25
+	values := []testItem{
+		{Id: 1, Name: "sword", Rate: &rate, IsLimited: true, StartAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), TagIds: []uint8{1, 2}, Memo: "memo"},
+		{Id: 2, Name: "shield", StartAt: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	want := map[Key]string{
+		{Id: 1, Key: "id"}:       "1",
+		{Id: 1, Key: "name"}:     "sword",
+		{Id: 1, Key: "rate"}:     "0.25",
+		{Id: 1, Key: "limited"}:  "true",
+		{Id: 1, Key: "start_at"}: "2023-01-01",
+		{Id: 1, Key: "tag_ids"}:  "1;2",
+		{Id: 2, Key: "id"}:       "2",
+		{Id: 2, Key: "name"}:     "shield",
+		{Id: 2, Key: "limited"}:  "false",
+		{Id: 2, Key: "start_at"}: "2023-02-01",
+		{Id: 2, Key: "tag_ids"}:  "",
+	}
+
+	got, err := Encode(values)
+	if err != nil {
+		t.Errorf("Encode() error = %v", err)
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Encode() got = %v, want %v", got, want)
+	}
+
+	decoded, err := DecodeMap[testItem](got)
+	values[0].Memo = ""
+	values[1].TagIds = []uint8{}
+	if err != nil || !reflect.DeepEqual(decoded, values) {
+		t.Errorf("DecodeMap() got = %v, want %v, error = %v", decoded, values, err)
+	}
+}
+
+type testQuestStep struct {
+	QuestId string
+	Step    int
+	Reward  int
+}
+
+func TestEncodeWithSchema(t *testing.T) {
+	schema := &Schema{KeyColumns: []string{"quest_id", "step"}}
+	values := []testQuestStep{
+		{QuestId: "main:1", Step: 2, Reward: 250},
+		{QuestId: "main", Step: 10, Reward: 300},
+	}
+	want := map[Key]string{
+		codeRowId("main:1", "2").Key("quest_id"): "main:1",
+		codeRowId("main:1", "2").Key("step"):     "2",
+		codeRowId("main:1", "2").Key("reward"):   "250",
+		codeRowId("main", "10").Key("quest_id"):  "main",
+		codeRowId("main", "10").Key("step"):      "10",
+		codeRowId("main", "10").Key("reward"):    "300",
+	}
+
+	got, err := EncodeWithSchema(values, schema)
+	if err != nil {
+		t.Errorf("EncodeWithSchema() error = %v", err)
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EncodeWithSchema() got = %v, want %v", got, want)
+	}
+
+	m := NewTabular("quest_steps", "csv", got, false)
+	m.SetSchema(schema)
+	decoded, err := Decode[testQuestStep](m)
+	if err != nil || !reflect.DeepEqual(decoded, []testQuestStep{values[1], values[0]}) {
+		t.Errorf("Decode() got = %v, error = %v", decoded, err)
+	}
+
+	if _, err = EncodeWithSchema([]testQuestStep{{Step: 1}}, schema); err == nil {
+		t.Errorf("EncodeWithSchema() error = nil, want error of the empty key")
+	}
+	if _, err = Encode(values); err == nil {
+		t.Errorf("Encode() error = nil, want error of the missing id field")
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	tests := []struct {
+		fieldName string
+		want      string
+	}{
+		{fieldName: "Id", want: "id"},
+		{fieldName: "StartAt", want: "start_at"},
+		{fieldName: "ItemID", want: "item_id"},
+		{fieldName: "HTTPStatus2", want: "http_status2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fieldName, func(t *testing.T) {
+			if got := toSnakeCase(tt.fieldName); got != tt.want {
+				t.Errorf("toSnakeCase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode_DeletedRow(t *testing.T) {
+	type drop struct {
+		Id     int
+		ItemId int
+	}
+	m := NewTabular("drops", "csv", map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "item_id"}: "1",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "item_id"}: "9",
+	}, false)
+	if err := m.LoadByDirectoryPath("./testdata/reference_delete"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Decode[drop](m)
+	if want := []drop{{Id: 1, ItemId: 1}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() got = %v, error = %v, want %v", got, err, want)
+	}
+}
@@ -0,0 +1,201 @@
+package table
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/go-support-tool/array"
+	"github.com/stepupdream/go-support-tool/directory"
+)
+
+// Changeset is the set of operations that converts one snapshot of a table into another.
+type Changeset struct {
+	name      string
+	extension string
+	schema    *Schema
+	columns   []string
+	Inserts   map[Key]string
+	Updates   map[Key]string
+	Deletes   map[Key]string
+}
+
+// Diff Compute the operations that convert the base snapshot into the target snapshot.
+// Only the rows that were added, changed or removed are included. An updated row contains every column of the target row.
+// A column removed from an existing row is written as the null marker of the target schema.
+// If the schema has no null marker, it cannot be expressed by an update file, so it is an error.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Diff(base *MasterData, target *MasterData) (*Changeset, error) {
+	changeset := &Changeset{
+		name:      target.name,
+		extension: target.extension,
+		schema:    target.schema,
+		columns:   target.Columns(),
+		Inserts:   make(map[Key]string),
+		Updates:   make(map[Key]string),
+		Deletes:   make(map[Key]string),
+	}
+	for _, column := range base.Columns() {
+		if !array.Contains(changeset.columns, column) {
+			changeset.columns = append(changeset.columns, column)
+		}
+	}
+
+	baseRows := existingRows(base)
+	targetRows := existingRows(target)
+
+	for id, baseRow := range baseRows {
+		targetRow, ok := targetRows[id]
+		if !ok {
+			copyRow(changeset.Deletes, id, baseRow)
+			continue
+		}
+
+		if rowEqual(baseRow, targetRow) {
+			continue
+		}
+		copyRow(changeset.Updates, id, targetRow)
+		for column := range baseRow {
+			if _, exists := targetRow[column]; exists {
+				continue
+			}
+			if target.schema == nil || target.schema.NullMarker == "" {
+				return nil, errors.New("A column removed from an existing row cannot be updated : id " + id.String() + " column " + column)
+			}
+			changeset.Updates[id.Key(column)] = target.schema.NullMarker
+		}
+	}
+
+	for id, targetRow := range targetRows {
+		if _, ok := baseRows[id]; !ok {
+			copyRow(changeset.Inserts, id, targetRow)
+		}
+	}
+
+	return changeset, nil
+}
+
+// IsEmpty Check if the changeset has no operation.
+func (c *Changeset) IsEmpty() bool {
+	return len(c.Inserts) == 0 && len(c.Updates) == 0 && len(c.Deletes) == 0
+}
+
+// Write Write the changeset into the specified version directory.
+// The files are written in the insert, update and delete directories in the layout that LoadByDirectoryPath reads.
+// A directory is not created for an operation without rows.
+// A file cannot express a missing value, so the rows with different columns are written into separate files
+// in numbered subdirectories. (ex. insert/1/items.csv and insert/2/items.csv)
+func (c *Changeset) Write(directoryPath string) error {
+	pathSeparator := string(os.PathSeparator)
+	operations := map[string]map[Key]string{
+		"delete": c.Deletes,
+		"update": c.Updates,
+		"insert": c.Inserts,
+	}
+
+	for _, loadType := range []string{"delete", "update", "insert"} {
+		if len(operations[loadType]) == 0 {
+			continue
+		}
+
+		groups := groupByColumns(operations[loadType])
+		for i, group := range groups {
+			groupPath := directoryPath + pathSeparator + loadType
+			if len(groups) > 1 {
+				groupPath += pathSeparator + strconv.Itoa(i+1)
+			}
+			if err := directory.Create(groupPath, false); err != nil {
+				return err
+			}
+
+			m := NewTabular(c.name, "", group, false)
+			m.SetSchema(c.schema)
+			m.SetColumns(c.columns)
+			if err := m.Export(groupPath + pathSeparator + c.name + c.extension); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// groupByColumns Split the values into groups of the rows that have the same columns.
+// The groups are ordered by their first row id.
+func groupByColumns(valueMap map[Key]string) []map[Key]string {
+	rows := groupById(valueMap)
+	ids := make([]RowId, 0, len(rows))
+	for id := range rows {
+		ids = append(ids, id)
+	}
+	SortRowIds(ids)
+
+	var groups []map[Key]string
+	groupNumbers := make(map[string]int)
+	for _, id := range ids {
+		columns := make([]string, 0, len(rows[id]))
+		for column := range rows[id] {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		columnSet := strings.Join(columns, "\x00")
+
+		groupNumber, ok := groupNumbers[columnSet]
+		if !ok {
+			groupNumber = len(groups)
+			groupNumbers[columnSet] = groupNumber
+			groups = append(groups, make(map[Key]string))
+		}
+		copyRow(groups[groupNumber], id, rows[id])
+	}
+
+	return groups
+}
+
+// existingRows Get the values of the rows of the table by row id.
+// The values left in a row without the key column, such as after a delete of only the id column, are not a row.
+func existingRows(m *MasterData) map[RowId]map[string]string {
+	r := make(map[RowId]map[string]string)
+	for _, id := range m.RowIds() {
+		r[id] = m.Row(id)
+	}
+
+	return r
+}
+
+// groupById Group the values of the map by the row id.
+func groupById(valueMap map[Key]string) map[RowId]map[string]string {
+	r := make(map[RowId]map[string]string)
+	for key, value := range valueMap {
+		if r[key.RowId()] == nil {
+			r[key.RowId()] = make(map[string]string)
+		}
+		r[key.RowId()][key.Key] = value
+	}
+
+	return r
+}
+
+// copyRow Copy the values of a row into the map.
+func copyRow(valueMap map[Key]string, id RowId, row map[string]string) {
+	for column, value := range row {
+		valueMap[id.Key(column)] = value
+	}
+}
+
+// rowEqual Check if both rows have the same columns and values.
+func rowEqual(row1 map[string]string, row2 map[string]string) bool {
+	if len(row1) != len(row2) {
+		return false
+	}
+	for column, value := range row1 {
+		if other, ok := row2[column]; !ok || other != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,178 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	base, err := Replay("samples", "csv", false, "./testdata/versions", "", "1_0_0_0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := Replay("samples", "csv", false, "./testdata/versions", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changeset, err := Diff(base, target)
+	if err != nil {
+		t.Errorf("Diff() error = %v", err)
+		return
+	}
+	want := &Changeset{
+		name:      "samples",
+		extension: ".csv",
+		columns:   []string{"id", "sample", "level"},
+		Inserts: map[Key]string{
+			{Id: 100, Key: "id"}:     "100",
+			{Id: 100, Key: "sample"}: "AAA",
+			{Id: 100, Key: "level"}:  "1000",
+		},
+		Updates: map[Key]string{
+			{Id: 2, Key: "id"}:     "2",
+			{Id: 2, Key: "sample"}: "ccc",
+			{Id: 2, Key: "level"}:  "700",
+		},
+		Deletes: map[Key]string{
+			{Id: 1, Key: "id"}:     "1",
+			{Id: 1, Key: "sample"}: "aaa",
+			{Id: 1, Key: "level"}:  "5",
+		},
+	}
+	if !reflect.DeepEqual(changeset, want) {
+		t.Errorf("Diff() got = %v, want %v", changeset, want)
+	}
+
+	directoryPath := t.TempDir()
+	if err = changeset.Write(directoryPath); err != nil {
+		t.Errorf("Write() error = %v", err)
+		return
+	}
+	if err = base.LoadByDirectoryPath(directoryPath); err != nil {
+		t.Errorf("LoadByDirectoryPath() error = %v", err)
+		return
+	}
+	if !reflect.DeepEqual(base.Rows, target.Rows) {
+		t.Errorf("LoadByDirectoryPath() got = %v, want %v", base.Rows, target.Rows)
+	}
+}
+
+func TestDiff_Error(t *testing.T) {
+	base := NewTabular("samples", "csv", map[Key]string{
+		{Id: 1, Key: "id"}:     "1",
+		{Id: 1, Key: "sample"}: "aaa",
+		{Id: 1, Key: "level"}:  "5",
+	}, false)
+	target := NewTabular("samples", "csv", map[Key]string{
+		{Id: 1, Key: "id"}:     "1",
+		{Id: 1, Key: "sample"}: "bbb",
+	}, false)
+
+	if _, err := Diff(base, target); err == nil {
+		t.Errorf("Diff() error = nil, want error")
+	}
+
+	changeset, err := Diff(base, base)
+	if err != nil || !changeset.IsEmpty() {
+		t.Errorf("Diff() got = %v, error = %v, want empty", changeset, err)
+	}
+}
+
+func TestDiff_NullMarker(t *testing.T) {
+	schema := &Schema{Empty: EmptyAsAbsent, NullMarker: "NULL"}
+	base := NewTabular("samples", "csv", map[Key]string{
+		{Id: 1, Key: "id"}:     "1",
+		{Id: 1, Key: "sample"}: "aaa",
+		{Id: 1, Key: "level"}:  "5",
+	}, false)
+	base.SetSchema(schema)
+	target := NewTabular("samples", "csv", map[Key]string{
+		{Id: 1, Key: "id"}:     "1",
+		{Id: 1, Key: "sample"}: "bbb",
+	}, false)
+	target.SetSchema(schema)
+
+	changeset, err := Diff(base, target)
+	if err != nil {
+		t.Errorf("Diff() error = %v", err)
+		return
+	}
+	directoryPath := t.TempDir()
+	if err = changeset.Write(directoryPath); err != nil {
+		t.Errorf("Write() error = %v", err)
+		return
+	}
+	if err = base.LoadByDirectoryPath(directoryPath); err != nil {
+		t.Errorf("LoadByDirectoryPath() error = %v", err)
+		return
+	}
+	if !reflect.DeepEqual(base.Rows, target.Rows) {
+		t.Errorf("LoadByDirectoryPath() got = %v, want %v", base.Rows, target.Rows)
+	}
+}
+
+func TestChangeset_Write_DifferentColumns(t *testing.T) {
+	base := NewTabular("samples", "csv", map[Key]string{
+		{Id: 3, Key: "id"}: "3",
+		{Id: 3, Key: "a"}:  "x",
+		{Id: 4, Key: "id"}: "4",
+		{Id: 4, Key: "b"}:  "y",
+	}, false)
+	target := NewTabular("samples", "csv", map[Key]string{
+		{Id: 1, Key: "id"}: "1",
+		{Id: 1, Key: "a"}:  "a",
+		{Id: 2, Key: "id"}: "2",
+		{Id: 2, Key: "b"}:  "b",
+	}, false)
+
+	changeset, err := Diff(base, target)
+	if err != nil {
+		t.Errorf("Diff() error = %v", err)
+		return
+	}
+	directoryPath := t.TempDir()
+	if err = changeset.Write(directoryPath); err != nil {
+		t.Errorf("Write() error = %v", err)
+		return
+	}
+	if err = base.LoadByDirectoryPath(directoryPath); err != nil {
+		t.Errorf("LoadByDirectoryPath() error = %v", err)
+		return
+	}
+	if !reflect.DeepEqual(base.Rows, target.Rows) {
+		t.Errorf("LoadByDirectoryPath() got = %v, want %v", base.Rows, target.Rows)
+	}
+}
+
+func TestDiff_DeletedRow(t *testing.T) {
+	newTable := func() *MasterData {
+		return NewTabular("drops", "csv", map[Key]string{
+			{Id: 1, Key: "id"}: "1", {Id: 1, Key: "item_id"}: "1",
+			{Id: 2, Key: "id"}: "2", {Id: 2, Key: "item_id"}: "9",
+		}, false)
+	}
+	base := newTable()
+	target := newTable()
+	if err := target.LoadByDirectoryPath("./testdata/reference_delete"); err != nil {
+		t.Fatal(err)
+	}
+
+	changeset, err := Diff(base, target)
+	if err != nil {
+		t.Errorf("Diff() error = %v", err)
+		return
+	}
+	directoryPath := t.TempDir()
+	if err = changeset.Write(directoryPath); err != nil {
+		t.Errorf("Write() error = %v", err)
+		return
+	}
+	if err = base.LoadByDirectoryPath(directoryPath); err != nil {
+		t.Errorf("LoadByDirectoryPath() error = %v", err)
+		return
+	}
+	if got, want := base.ToRows(), target.ToRows(); !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadByDirectoryPath() got = %v, want %v", got, want)
+	}
+}
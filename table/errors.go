@@ -0,0 +1,95 @@
+package table
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ErrorKind is the kind of problem found in a table file.
+type ErrorKind string
+
+const (
+	KindMissingKeyColumn    ErrorKind = "missing_key_column"
+	KindNotNumericId        ErrorKind = "not_numeric_id"
+	KindEmptyKey            ErrorKind = "empty_key"
+	KindDuplicateKey        ErrorKind = "duplicate_key"
+	KindEmptyValue          ErrorKind = "empty_value"
+	KindInvalidValue        ErrorKind = "invalid_value"
+	KindNotUniqueId         ErrorKind = "not_unique_id"
+	KindInsertExistingId    ErrorKind = "insert_existing_id"
+	KindUpdateMissingId     ErrorKind = "update_missing_id"
+	KindUpdateMissingColumn ErrorKind = "update_missing_column"
+	KindDeleteMissingId     ErrorKind = "delete_missing_id"
+	KindUpsertMissingColumn ErrorKind = "upsert_missing_column"
+	KindInvalidWindow       ErrorKind = "invalid_window"
+	KindOverlappingWindow   ErrorKind = "overlapping_window"
+)
+
+// ErrorMode decides whether loading stops at the first problem.
+type ErrorMode int
+
+const (
+	// ErrorModeCollect checks every file and reports every problem.
+	ErrorModeCollect ErrorMode = iota
+	// ErrorModeFailFast stops at the first problem and reports only it.
+	ErrorModeFailFast
+)
+
+// ValidationError is a problem found in a table file.
+type ValidationError struct {
+	Kind     ErrorKind
+	FilePath string
+	// Line is the line number in the file, and RowNumber is the number of the row excluding the comment rows.
+	Line      int
+	RowNumber int
+	Id        string
+	Column    string
+	Value     string
+	Message   string
+}
+
+// Error returns the message with the position of the problem.
+func (e ValidationError) Error() string {
+	message := e.Message + " : " + e.FilePath
+	if e.RowNumber > 0 {
+		message += " rowNumber : " + strconv.Itoa(e.RowNumber)
+	}
+	if e.Line > 0 {
+		message += " line : " + strconv.Itoa(e.Line)
+	}
+	if e.Id != "" {
+		message += " id : " + e.Id
+	}
+	if e.Column != "" {
+		message += " column : " + e.Column
+	}
+
+	return message
+}
+
+// ValidationErrors is a list of ValidationError.
+type ValidationErrors []ValidationError
+
+// Error returns the messages of all errors separated by a line break.
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, validationError := range e {
+		messages = append(messages, validationError.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// orNil Get the errors as an error, or nil if there is no error.
+func (e ValidationErrors) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// SetErrorMode Set whether loading stops at the first problem. The default is ErrorModeCollect.
+func (m *MasterData) SetErrorMode(errorMode ErrorMode) {
+	m.errorMode = errorMode
+}
@@ -0,0 +1,62 @@
+package table
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadByDirectoryPath_ErrorMode(t *testing.T) {
+	collectPath := filepath.Join("testdata", "collect")
+	tests := []struct {
+		name      string
+		errorMode ErrorMode
+		want      ValidationErrors
+	}{
+		{
+			name:      "ErrorMode1",
+			errorMode: ErrorModeCollect,
+			want: ValidationErrors{
+				{Kind: KindDeleteMissingId, FilePath: filepath.Join(collectPath, "delete", "samples.csv"), Line: 2, Id: "98", Message: "Attempted to delete a non-existent ID"},
+				{Kind: KindUpdateMissingId, FilePath: filepath.Join(collectPath, "update", "samples.csv"), Line: 4, Id: "99", Message: "Tried to update a non-existent ID"},
+				{Kind: KindNotUniqueId, FilePath: filepath.Join(collectPath, "insert", "samples.csv"), Line: 2, Id: "1", Message: "ID is not unique"},
+				{Kind: KindEmptyValue, FilePath: filepath.Join(collectPath, "insert", "sub", "samples.csv"), Line: 2, RowNumber: 1, Id: "3", Column: "level", Message: "Empty value"},
+			},
+		},
+		{
+			name:      "ErrorMode2",
+			errorMode: ErrorModeFailFast,
+			want: ValidationErrors{
+				{Kind: KindDeleteMissingId, FilePath: filepath.Join(collectPath, "delete", "samples.csv"), Line: 2, Id: "98", Message: "Attempted to delete a non-existent ID"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewTabular("samples", "csv", testJournalRows(), false)
+			m.SetErrorMode(tt.errorMode)
+			err := m.LoadByDirectoryPath("./" + collectPath)
+
+			var validationErrors ValidationErrors
+			if !errors.As(err, &validationErrors) {
+				t.Errorf("LoadByDirectoryPath() error = %v, want ValidationErrors", err)
+				return
+			}
+			if !reflect.DeepEqual(validationErrors, tt.want) {
+				t.Errorf("LoadByDirectoryPath() error = %v, want %v", validationErrors, tt.want)
+			}
+			if !reflect.DeepEqual(m.Rows, testJournalRows()) {
+				t.Errorf("LoadByDirectoryPath() changed the rows : %v", m.Rows)
+			}
+		})
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	validationError := ValidationError{Kind: KindEmptyValue, FilePath: "a.csv", Line: 3, RowNumber: 2, Id: "5", Column: "level", Message: "Empty value"}
+	want := "Empty value : a.csv rowNumber : 2 line : 3 id : 5 column : level"
+	if got := validationError.Error(); got != want {
+		t.Errorf("Error() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+package table
+
+import (
+	"sort"
+
+	"github.com/stepupdream/go-support-tool/array"
+	"github.com/stepupdream/go-support-tool/delimited"
+)
+
+// Columns Get the column names of the table.
+// The order follows the headers of the loaded files, and the columns that only exist in Rows follow in name order.
+// The key columns always come first.
+func (m *MasterData) Columns() []string {
+	exists := map[string]bool{}
+	for key := range m.Rows {
+		exists[key.Key] = true
+	}
+
+	keyColumns := m.schema.keyColumns()
+	columns := append([]string{}, keyColumns...)
+	for _, column := range m.columns {
+		if !array.Contains(keyColumns, column) && (exists[column] || len(m.Rows) == 0) {
+			columns = append(columns, column)
+		}
+	}
+
+	var extraColumns []string
+	for column := range exists {
+		if !array.Contains(columns, column) {
+			extraColumns = append(extraColumns, column)
+		}
+	}
+	sort.Strings(extraColumns)
+
+	return append(columns, extraColumns...)
+}
+
+// SetColumns Set the order of the columns used for the export.
+func (m *MasterData) SetColumns(columns []string) {
+	m.columns = append([]string{}, columns...)
+}
+
+// addColumns Add the columns that are not yet known, keeping the order of the first appearance.
+func (m *MasterData) addColumns(columns []string) {
+	for _, column := range columns {
+		if !array.Contains(m.columns, column) {
+			m.columns = append(m.columns, column)
+		}
+	}
+}
+
+// ToRows Convert the table into a two-dimensional array with the header in the first row.
+// The rows are sorted by the row id. A missing value is output as an empty string.
+func (m *MasterData) ToRows() [][]string {
+	columns := m.Columns()
+	rows := [][]string{columns}
+
+	for _, id := range m.RowIds() {
+		row := make([]string, 0, len(columns))
+		for _, column := range columns {
+			row = append(row, m.Rows[id.Key(column)])
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// Export Write the table to the specified file.
+// The file is written with BOM, and the separator is decided by the extension. (tsv is tab, otherwise comma)
+func (m *MasterData) Export(path string) error {
+	return delimited.CreateNewFile(path, m.ToRows())
+}
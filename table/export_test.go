@@ -0,0 +1,88 @@
+package table
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stepupdream/go-support-tool/delimited"
+)
+
+func TestColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		rows    map[Key]string
+		want    []string
+	}{
+		{
+			name:    "Columns1",
+			columns: nil,
+			rows: map[Key]string{
+				{Id: 1, Key: "sample"}: "aaa",
+				{Id: 1, Key: "id"}:     "1",
+				{Id: 1, Key: "level"}:  "5",
+			},
+			want: []string{"id", "level", "sample"},
+		},
+		{
+			name:    "Columns2",
+			columns: []string{"sample", "id", "level", "deleted"},
+			rows: map[Key]string{
+				{Id: 1, Key: "sample"}: "aaa",
+				{Id: 1, Key: "id"}:     "1",
+				{Id: 1, Key: "level"}:  "5",
+				{Id: 1, Key: "extra"}:  "x",
+			},
+			want: []string{"id", "sample", "level", "extra"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewTabular("samples", "csv", tt.rows, false)
+			m.SetColumns(tt.columns)
+			if got := m.Columns(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Columns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	m, err := Replay("samples", "csv", false, "./testdata/versions", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, extension := range []string{".csv", ".tsv"} {
+		path := filepath.Join(t.TempDir(), "samples"+extension)
+		if err = m.Export(path); err != nil {
+			t.Errorf("Export() error = %v", err)
+			return
+		}
+
+		rows, err := delimited.Load(path, true, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := [][]string{
+			{"id", "sample", "level"},
+			{"2", "ccc", "700"},
+			{"100", "AAA", "1000"},
+		}
+		if !reflect.DeepEqual(rows, want) {
+			t.Errorf("Export() rows = %v, want %v", rows, want)
+		}
+
+		content, _ := os.ReadFile(path)
+		if extension == ".tsv" && !reflect.DeepEqual(content[3:12], []byte("id\tsample")) {
+			t.Errorf("Export() is not separated by tab : %q", content)
+		}
+
+		loaded, err := LoadMap(path)
+		if err != nil || !reflect.DeepEqual(loaded, m.Rows) {
+			t.Errorf("LoadMap() got = %v, want %v, error = %v", loaded, m.Rows, err)
+		}
+	}
+}
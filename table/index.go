@@ -0,0 +1,102 @@
+package table
+
+import "reflect"
+
+// rowStore is the row-oriented storage of the values of the table.
+// It lets the operations find a row in time proportional to the size of the edit instead of the size of the table.
+// Rows is the cell view of the same values. SetValue and RemoveValue change both together.
+type rowStore struct {
+	// rows is the values of each row by column.
+	rows map[RowId]map[string]string
+	// cells is the number of stored values. It is compared with the length of Rows to detect direct changes to Rows.
+	cells int
+	// source is the address of the Rows map that the store was built from, to detect that Rows was replaced.
+	source uintptr
+}
+
+// Reindex Rebuild the row store from Rows.
+// Call it after changing Rows directly instead of through SetValue, RemoveValue or the load methods.
+// A direct change that adds or removes values, or replaces Rows, is detected, but one that keeps the number of values is not.
+func (m *MasterData) Reindex() {
+	m.store = &rowStore{rows: make(map[RowId]map[string]string), source: reflect.ValueOf(m.Rows).Pointer()}
+	for key, value := range m.Rows {
+		m.store.set(key, value)
+	}
+}
+
+// rowStore Get the row store, building it if it does not exist or is out of date.
+func (m *MasterData) rowStore() *rowStore {
+	if m.store == nil || m.store.cells != len(m.Rows) || m.store.source != reflect.ValueOf(m.Rows).Pointer() {
+		m.Reindex()
+	}
+
+	return m.store
+}
+
+// HasRow Check if the row exists. A row exists when the value of its first key column exists.
+// Values left in a row without the key column, such as after a delete of only the id column, do not make a row.
+func (m *MasterData) HasRow(id RowId) bool {
+	return m.rowStore().exists(id, m.schema.keyColumns()[0])
+}
+
+// RowIds Get the ids of all rows in ascending order. Only the rows that HasRow finds are returned.
+func (m *MasterData) RowIds() []RowId {
+	store := m.rowStore()
+	keyColumn := m.schema.keyColumns()[0]
+	r := make([]RowId, 0, len(store.rows))
+	for id := range store.rows {
+		if store.exists(id, keyColumn) {
+			r = append(r, id)
+		}
+	}
+
+	SortRowIds(r)
+
+	return r
+}
+
+// Row Get the values of the specified row by column. If the row does not exist, return an empty map.
+func (m *MasterData) Row(id RowId) map[string]string {
+	r := make(map[string]string)
+	store := m.rowStore()
+	if !store.exists(id, m.schema.keyColumns()[0]) {
+		return r
+	}
+	for column, value := range store.rows[id] {
+		r[column] = value
+	}
+
+	return r
+}
+
+// exists Check if the row has the value of the key column.
+func (s *rowStore) exists(id RowId, keyColumn string) bool {
+	_, ok := s.rows[id][keyColumn]
+	return ok
+}
+
+// set Set the value of the key.
+func (s *rowStore) set(key Key, value string) {
+	id := key.RowId()
+	if s.rows[id] == nil {
+		s.rows[id] = make(map[string]string)
+	}
+	if _, ok := s.rows[id][key.Key]; !ok {
+		s.cells++
+	}
+	s.rows[id][key.Key] = value
+}
+
+// remove Remove the value of the key.
+func (s *rowStore) remove(key Key) {
+	id := key.RowId()
+	if _, ok := s.rows[id][key.Key]; !ok {
+		return
+	}
+
+	delete(s.rows[id], key.Key)
+	s.cells--
+	if len(s.rows[id]) == 0 {
+		delete(s.rows, id)
+	}
+}
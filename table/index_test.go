@@ -0,0 +1,151 @@
+package table
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestMasterData_HasRow(t *testing.T) {
+	m := NewTabular("items", "csv", testJournalRows(), false)
+
+	if !m.HasRow(RowId{Id: 1}) || m.HasRow(RowId{Id: 3}) {
+		t.Errorf("HasRow() got wrong result before load")
+	}
+
+	m.Rows[Key{Id: 3, Key: "id"}] = "3"
+	if !m.HasRow(RowId{Id: 3}) {
+		t.Errorf("HasRow() does not find a row added to Rows directly")
+	}
+
+	m.RemoveValue(Key{Id: 1, Key: "id"})
+	if m.HasRow(RowId{Id: 1}) {
+		t.Errorf("HasRow() finds a removed row")
+	}
+}
+
+func TestMasterData_HasRow_SameCount(t *testing.T) {
+	m := NewTabular("items", "csv", map[Key]string{{Id: 1, Key: "id"}: "1", {Id: 2, Key: "id"}: "2"}, false)
+	m.HasRow(RowId{Id: 1})
+	m.RemoveValue(Key{Id: 1, Key: "id"})
+	m.SetValue(Key{Id: 3, Key: "id"}, "3")
+	if m.HasRow(RowId{Id: 1}) || !m.HasRow(RowId{Id: 3}) {
+		t.Errorf("HasRow() is out of date after SetValue and RemoveValue")
+	}
+
+	delete(m.Rows, Key{Id: 2, Key: "id"})
+	m.Rows[Key{Id: 4, Key: "id"}] = "4"
+	m.Reindex()
+	if m.HasRow(RowId{Id: 2}) || !m.HasRow(RowId{Id: 4}) {
+		t.Errorf("HasRow() is out of date after Reindex")
+	}
+
+	m.Rows = map[Key]string{{Id: 5, Key: "id"}: "5", {Id: 6, Key: "id"}: "6"}
+	if m.HasRow(RowId{Id: 3}) || !m.HasRow(RowId{Id: 5}) {
+		t.Errorf("HasRow() is out of date after Rows is replaced")
+	}
+}
+
+func TestMasterData_RowIds_LeftoverValues(t *testing.T) {
+	m := NewTabular("items", "csv", testJournalRows(), false)
+	m.RemoveValue(Key{Id: 1, Key: "id"})
+
+	if m.HasRow(RowId{Id: 1}) {
+		t.Errorf("HasRow() finds a row without the key column")
+	}
+	if got, want := m.RowIds(), []RowId{{Id: 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RowIds() = %v, want %v", got, want)
+	}
+	if got := m.Row(RowId{Id: 1}); len(got) != 0 {
+		t.Errorf("Row() = %v, want empty", got)
+	}
+}
+
+func TestMasterData_RowIds(t *testing.T) {
+	m := NewTabular("items", "csv", testJournalRows(), false)
+	m.begin()
+	m.SetValue(Key{Id: 10, Key: "id"}, "10")
+	for _, column := range []string{"id", "sample", "level"} {
+		m.RemoveValue(Key{Id: 1, Key: column})
+	}
+
+	if got, want := m.RowIds(), []RowId{{Id: 2}, {Id: 10}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RowIds() = %v, want %v", got, want)
+	}
+
+	m.rollback()
+	if got, want := m.RowIds(), []RowId{{Id: 1}, {Id: 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RowIds() after rollback = %v, want %v", got, want)
+	}
+}
+
+func TestMasterData_Row(t *testing.T) {
+	m := NewTabular("items", "csv", testJournalRows(), false)
+
+	want := map[string]string{"id": "1", "sample": "aaa", "level": "5"}
+	if got := m.Row(RowId{Id: 1}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Row() = %v, want %v", got, want)
+	}
+	if got := m.Row(RowId{Id: 3}); len(got) != 0 {
+		t.Errorf("Row() = %v, want empty", got)
+	}
+}
+
+const (
+	benchmarkRowCount    = 100000
+	benchmarkColumnCount = 10
+)
+
+// benchmarkTable Create a table with a million cells and a version directory that edits a few of its rows.
+func benchmarkTable(b *testing.B) (*MasterData, string) {
+	rows := make(map[Key]string, benchmarkRowCount*benchmarkColumnCount)
+	for id := 1; id <= benchmarkRowCount; id++ {
+		rows[Key{Id: id, Key: "id"}] = strconv.Itoa(id)
+		for column := 1; column < benchmarkColumnCount; column++ {
+			rows[Key{Id: id, Key: "column" + strconv.Itoa(column)}] = strconv.Itoa(id * column)
+		}
+	}
+
+	directoryPath := b.TempDir()
+	files := map[string]string{
+		"insert": "id,column1\n" + strconv.Itoa(benchmarkRowCount+1) + ",1\n",
+		"update": "id,column1\n1,2\n2,3\n",
+		"delete": "id\n3\n",
+	}
+	for loadType, content := range files {
+		if err := os.MkdirAll(filepath.Join(directoryPath, loadType), 0755); err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(directoryPath, loadType, "items.csv"), []byte(content), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	return NewTabular("items", "csv", rows, false), directoryPath
+}
+
+func BenchmarkMasterData_ValidateDirectoryPath(b *testing.B) {
+	m, directoryPath := benchmarkTable(b)
+	m.Reindex()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := m.ValidateDirectoryPath(directoryPath); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPluckId is the cost of the linear scan that each operation used to make to find the existing ids.
+func BenchmarkPluckId(b *testing.B) {
+	m, _ := benchmarkTable(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if ids := PluckId(m.Rows); len(ids) != benchmarkRowCount {
+			b.Fatal("unexpected ids")
+		}
+	}
+}
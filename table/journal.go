@@ -0,0 +1,134 @@
+package table
+
+// journal records the state of the values before they are changed, so that the changes can be rolled back.
+type journal struct {
+	// rows is the value of each changed key before the change. nil means the key did not exist.
+	rows       map[Key]*string
+	provenance map[Key]*Provenance
+	columns    []string
+}
+
+// begin Start recording the changes.
+func (m *MasterData) begin() {
+	m.journal = &journal{
+		rows:       make(map[Key]*string),
+		provenance: make(map[Key]*Provenance),
+		columns:    append([]string{}, m.columns...),
+	}
+}
+
+// commit Accept the recorded changes.
+func (m *MasterData) commit() {
+	m.journal = nil
+}
+
+// rollback Restore the state before begin was called.
+func (m *MasterData) rollback() {
+	if m.journal == nil {
+		return
+	}
+
+	j := m.journal
+	m.journal = nil
+	for key, value := range j.rows {
+		if value == nil {
+			m.RemoveValue(key)
+		} else {
+			m.SetValue(key, *value)
+		}
+	}
+	for key, provenance := range j.provenance {
+		if provenance == nil {
+			delete(m.provenance, key)
+		} else {
+			m.provenance[key] = *provenance
+		}
+	}
+	m.columns = j.columns
+}
+
+// SetValue Set the value of the key in Rows and in the row store.
+// During a load, the previous value is recorded so that the change is undone when the load fails.
+func (m *MasterData) SetValue(key Key, value string) {
+	store := m.rowStore()
+	m.recordValue(key)
+	m.recordChange(key)
+	m.Rows[key] = value
+	store.set(key, value)
+}
+
+// RemoveValue Remove the value of the key from Rows and from the row store.
+// During a load, the previous value is recorded so that the change is undone when the load fails.
+func (m *MasterData) RemoveValue(key Key) {
+	store := m.rowStore()
+	m.recordValue(key)
+	m.recordChange(key)
+	delete(m.Rows, key)
+	store.remove(key)
+}
+
+// recordValue Record the value of the key before its first change.
+func (m *MasterData) recordValue(key Key) {
+	if m.journal == nil {
+		return
+	}
+	if _, ok := m.journal.rows[key]; ok {
+		return
+	}
+
+	if value, ok := m.Rows[key]; ok {
+		m.journal.rows[key] = &value
+	} else {
+		m.journal.rows[key] = nil
+	}
+}
+
+// trackChanges Start collecting the keys changed by SetValue and RemoveValue, when provenance is enabled.
+func (m *MasterData) trackChanges() {
+	if m.provenance != nil {
+		m.changes = make(map[Key]bool)
+	}
+}
+
+// untrackChanges Stop collecting the changed keys and get them.
+func (m *MasterData) untrackChanges() map[Key]bool {
+	changes := m.changes
+	m.changes = nil
+
+	return changes
+}
+
+// recordChange Record that the value of the key is changed.
+func (m *MasterData) recordChange(key Key) {
+	if m.changes != nil {
+		m.changes[key] = true
+	}
+}
+
+// setProvenance Set the provenance of the key, recording the previous one.
+func (m *MasterData) setProvenance(key Key, provenance Provenance) {
+	m.recordProvenanceChange(key)
+	m.provenance[key] = provenance
+}
+
+// removeProvenance Remove the provenance of the key, recording the previous one.
+func (m *MasterData) removeProvenance(key Key) {
+	m.recordProvenanceChange(key)
+	delete(m.provenance, key)
+}
+
+// recordProvenanceChange Record the provenance of the key before its first change.
+func (m *MasterData) recordProvenanceChange(key Key) {
+	if m.journal == nil {
+		return
+	}
+	if _, ok := m.journal.provenance[key]; ok {
+		return
+	}
+
+	if provenance, ok := m.provenance[key]; ok {
+		m.journal.provenance[key] = &provenance
+	} else {
+		m.journal.provenance[key] = nil
+	}
+}
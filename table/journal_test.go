@@ -0,0 +1,62 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testJournalRows() map[Key]string {
+	return map[Key]string{
+		{Id: 1, Key: "id"}:     "1",
+		{Id: 1, Key: "sample"}: "aaa",
+		{Id: 1, Key: "level"}:  "5",
+		{Id: 2, Key: "id"}:     "2",
+		{Id: 2, Key: "sample"}: "bbb",
+		{Id: 2, Key: "level"}:  "43",
+	}
+}
+
+func TestLoadByDirectoryPath_Rollback(t *testing.T) {
+	m := NewTabular("samples", "csv", testJournalRows(), false)
+	m.SetColumns([]string{"id", "sample", "level"})
+	m.EnableProvenance()
+
+	if err := m.LoadByDirectoryPath("./testdata/atomic"); err == nil {
+		t.Errorf("LoadByDirectoryPath() error = nil, want error")
+		return
+	}
+	if !reflect.DeepEqual(m.Rows, testJournalRows()) {
+		t.Errorf("LoadByDirectoryPath() got = %v, want %v", m.Rows, testJournalRows())
+	}
+	if !reflect.DeepEqual(m.columns, []string{"id", "sample", "level"}) {
+		t.Errorf("LoadByDirectoryPath() columns = %v", m.columns)
+	}
+	if len(m.provenance) != 0 {
+		t.Errorf("LoadByDirectoryPath() provenance = %v", m.provenance)
+	}
+	if m.journal != nil {
+		t.Errorf("LoadByDirectoryPath() journal is not cleared")
+	}
+}
+
+func TestValidateDirectoryPath(t *testing.T) {
+	tests := []struct {
+		name          string
+		directoryPath string
+		wantErr       bool
+	}{
+		{name: "ValidateDirectoryPath1", directoryPath: "./testdata/pattern2", wantErr: false},
+		{name: "ValidateDirectoryPath2", directoryPath: "./testdata/atomic", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewTabular("samples", "csv", testJournalRows(), false)
+			if err := m.ValidateDirectoryPath(tt.directoryPath); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDirectoryPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(m.Rows, testJournalRows()) {
+				t.Errorf("ValidateDirectoryPath() changed the rows : %v", m.Rows)
+			}
+		})
+	}
+}
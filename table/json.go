@@ -0,0 +1,160 @@
+package table
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// WriteJSON Write the table to the writer as a JSON array of objects.
+// The rows are sorted by the row id, and the keys of each object follow the order of Columns.
+// Values are typed by the column of the schema (int and float are numbers, bool is a boolean) and are strings otherwise.
+// An empty value of a nullable column or of a column typed other than string is null, and a missing value is omitted.
+func (m *MasterData) WriteJSON(writer io.Writer) error {
+	return m.writeJSON(writer, false)
+}
+
+// WriteJSONLines Write the table to the writer as JSON Lines, one object per row.
+// See WriteJSON for the format of the objects.
+func (m *MasterData) WriteJSONLines(writer io.Writer) error {
+	return m.writeJSON(writer, true)
+}
+
+// ExportJSON Write the table to the specified file as a JSON array of objects.
+func (m *MasterData) ExportJSON(path string) error {
+	return exportFile(path, m.WriteJSON)
+}
+
+// ExportJSONLines Write the table to the specified file as JSON Lines.
+func (m *MasterData) ExportJSONLines(path string) error {
+	return exportFile(path, m.WriteJSONLines)
+}
+
+// exportFile Create the file and write to it with the write function.
+func exportFile(path string, write func(writer io.Writer) error) (err error) {
+	exportedFile, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := exportedFile.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	return write(exportedFile)
+}
+
+// writeJSON Write the rows as a JSON array, or as JSON Lines.
+func (m *MasterData) writeJSON(writer io.Writer, isLines bool) error {
+	bufferedWriter := bufio.NewWriter(writer)
+	columns := m.Columns()
+
+	separator, start, end := ",\n", "[\n", "\n]\n"
+	if isLines {
+		separator, start, end = "\n", "", "\n"
+	}
+
+	ids := m.RowIds()
+	if len(ids) == 0 {
+		start, end = "", ""
+		if !isLines {
+			start = "[]\n"
+		}
+	}
+
+	if _, err := bufferedWriter.WriteString(start); err != nil {
+		return err
+	}
+	for i, id := range ids {
+		object, err := m.jsonObject(id, columns)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			if _, err = bufferedWriter.WriteString(separator); err != nil {
+				return err
+			}
+		}
+		if _, err = bufferedWriter.Write(object); err != nil {
+			return err
+		}
+	}
+	if _, err := bufferedWriter.WriteString(end); err != nil {
+		return err
+	}
+
+	return bufferedWriter.Flush()
+}
+
+// jsonObject Convert the row into a JSON object whose keys follow the order of the columns.
+func (m *MasterData) jsonObject(id RowId, columns []string) ([]byte, error) {
+	object := []byte{'{'}
+	for _, column := range columns {
+		value, ok := m.Rows[id.Key(column)]
+		if !ok {
+			continue
+		}
+
+		jsonValue, err := m.jsonValue(column, value)
+		if err != nil {
+			return nil, errors.Wrap(err, "id : "+id.String()+" column : "+column)
+		}
+		jsonKey, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(object) > 1 {
+			object = append(object, ',')
+		}
+		object = append(object, jsonKey...)
+		object = append(object, ':')
+		object = append(object, jsonValue...)
+	}
+
+	return append(object, '}'), nil
+}
+
+// jsonValue Convert the value into a JSON value by the type of the column in the schema.
+func (m *MasterData) jsonValue(columnName string, value string) ([]byte, error) {
+	var column Column
+	var ok bool
+	if m.schema != nil {
+		column, ok = m.schema.Column(columnName)
+	}
+	if !ok {
+		return json.Marshal(value)
+	}
+	if column.isNull(value) {
+		return []byte("null"), nil
+	}
+
+	switch column.Type {
+	case TypeInt:
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, errors.New("Value is not int : " + value)
+		}
+		return json.Marshal(number)
+	case TypeFloat:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.New("Value is not float : " + value)
+		}
+		return json.Marshal(number)
+	case TypeBool:
+		boolValue, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("Value is not bool : " + value)
+		}
+		return json.Marshal(boolValue)
+	}
+
+	return json.Marshal(value)
+}
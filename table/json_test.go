@@ -0,0 +1,109 @@
+package table
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func testJSONTable() *MasterData {
+	m := NewTabular("items", "csv", map[Key]string{
+		{Id: 10, Key: "id"}: "10", {Id: 10, Key: "name"}: "shield", {Id: 10, Key: "rate"}: "", {Id: 10, Key: "is_limited"}: "false",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "sword", {Id: 2, Key: "rate"}: "1.5", {Id: 2, Key: "memo"}: "new",
+	}, false)
+	m.SetSchema(testSchema())
+	m.SetColumns([]string{"id", "name", "rate", "is_limited", "memo"})
+
+	return m
+}
+
+func testEmptyAsStringJSONTable() *MasterData {
+	m := NewTabular("items", "csv", map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "", {Id: 1, Key: "level"}: "", {Id: 1, Key: "is_limited"}: "", {Id: 1, Key: "start_at"}: "",
+	}, false)
+	m.SetSchema(&Schema{
+		Empty: EmptyAsString,
+		Columns: []Column{
+			{Name: "id", Type: TypeInt},
+			{Name: "name", Type: TypeString},
+			{Name: "level", Type: TypeInt},
+			{Name: "is_limited", Type: TypeBool},
+			{Name: "start_at", Type: TypeDate},
+		},
+	})
+	m.SetColumns([]string{"id", "name", "level", "is_limited", "start_at"})
+
+	return m
+}
+
+func TestMasterData_WriteJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		table   *MasterData
+		isLines bool
+		want    string
+	}{
+		{
+			name:  "WriteJSON1",
+			table: testJSONTable(),
+			want: "[\n" +
+				`{"id":2,"name":"sword","rate":1.5,"memo":"new"},` + "\n" +
+				`{"id":10,"name":"shield","rate":null,"is_limited":false}` + "\n]\n",
+		},
+		{
+			name:    "WriteJSON2",
+			table:   testJSONTable(),
+			isLines: true,
+			want: `{"id":2,"name":"sword","rate":1.5,"memo":"new"}` + "\n" +
+				`{"id":10,"name":"shield","rate":null,"is_limited":false}` + "\n",
+		},
+		{
+			name:  "WriteJSON3",
+			table: NewTabular("items", "csv", map[Key]string{}, false),
+			want:  "[]\n",
+		},
+		{
+			name:  "WriteJSON4",
+			table: testEmptyAsStringJSONTable(),
+			want:  "[\n" + `{"id":1,"name":"","level":null,"is_limited":null,"start_at":null}` + "\n]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bytes.Buffer
+			var err error
+			if tt.isLines {
+				err = tt.table.WriteJSONLines(&got)
+			} else {
+				err = tt.table.WriteJSON(&got)
+			}
+			if err != nil {
+				t.Errorf("WriteJSON() error = %v", err)
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("WriteJSON() got = %v, want %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestMasterData_ExportJSON(t *testing.T) {
+	m := testJSONTable()
+	m.Rows[Key{Id: 2, Key: "is_limited"}] = "yes"
+
+	path := t.TempDir() + string(os.PathSeparator) + "items.json"
+	if err := m.ExportJSON(path); err == nil {
+		t.Errorf("ExportJSON() error = nil, want error")
+	}
+
+	delete(m.Rows, Key{Id: 2, Key: "is_limited"})
+	if err := m.ExportJSONLines(path); err != nil {
+		t.Errorf("ExportJSONLines() error = %v", err)
+		return
+	}
+	got, err := os.ReadFile(path)
+	if err != nil || bytes.Count(got, []byte("\n")) != 2 {
+		t.Errorf("ExportJSONLines() got = %s, error = %v", got, err)
+	}
+}
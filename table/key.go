@@ -0,0 +1,181 @@
+package table
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// KeySeparator is the separator of the values of composite key columns in RowId.Code.
+// A separator or keyEscape inside a value is preceded by keyEscape, so different values never share a code.
+const KeySeparator = ":"
+
+// keyEscape is the character that escapes KeySeparator and itself in the values of RowId.Code.
+const keyEscape = `\`
+
+// DefaultKeyColumns is the key column used when the schema does not specify it.
+var DefaultKeyColumns = []string{"id"}
+
+// RowId identifies a row of a table.
+// A table keyed by a single int column uses Id, and a table keyed by string or composite columns uses Code.
+type RowId struct {
+	Id   int
+	Code string
+}
+
+// RowId Get the id of the row that the key belongs to.
+func (k Key) RowId() RowId {
+	return RowId{Id: k.Id, Code: k.Code}
+}
+
+// Key Get the key of the specified column of the row.
+func (r RowId) Key(column string) Key {
+	return Key{Id: r.Id, Key: column, Code: r.Code}
+}
+
+// String returns the text of the id used in messages.
+func (r RowId) String() string {
+	if r.Code != "" {
+		return r.Code
+	}
+
+	return strconv.Itoa(r.Id)
+}
+
+// Less Check if the row id comes before the other.
+// Codes are compared segment by segment. Numeric segments come before the others and are compared as numbers,
+// and segments of the same number, such as "01" and "1", are compared as strings so that the order is total.
+func (r RowId) Less(other RowId) bool {
+	if r.Id != other.Id {
+		return r.Id < other.Id
+	}
+	if r.Code == other.Code {
+		return false
+	}
+
+	segments1 := splitCode(r.Code)
+	segments2 := splitCode(other.Code)
+	for i := 0; i < len(segments1) && i < len(segments2); i++ {
+		if segments1[i] == segments2[i] {
+			continue
+		}
+		number1, err1 := strconv.Atoi(segments1[i])
+		number2, err2 := strconv.Atoi(segments2[i])
+		if err1 == nil && err2 == nil && number1 != number2 {
+			return number1 < number2
+		}
+		if (err1 == nil) != (err2 == nil) {
+			return err1 == nil
+		}
+		return segments1[i] < segments2[i]
+	}
+
+	return len(segments1) < len(segments2)
+}
+
+// Values Get the values of the key columns that the code is made of.
+// It returns nil for a row identified by Id.
+func (r RowId) Values() []string {
+	if r.Code == "" {
+		return nil
+	}
+
+	return splitCode(r.Code)
+}
+
+// codeRowId Get the id of the row identified by the values of string or composite key columns.
+func codeRowId(values ...string) RowId {
+	return RowId{Code: joinCode(values)}
+}
+
+// joinCode Join the values with KeySeparator, escaping the separator inside the values.
+func joinCode(values []string) string {
+	var builder strings.Builder
+	for i, value := range values {
+		if i > 0 {
+			builder.WriteString(KeySeparator)
+		}
+		for _, character := range value {
+			if string(character) == KeySeparator || string(character) == keyEscape {
+				builder.WriteString(keyEscape)
+			}
+			builder.WriteRune(character)
+		}
+	}
+
+	return builder.String()
+}
+
+// splitCode Split the code made by joinCode into the values.
+func splitCode(code string) []string {
+	values := make([]string, 0, 1)
+	var builder strings.Builder
+	escaped := false
+	for _, character := range code {
+		switch {
+		case escaped:
+			builder.WriteRune(character)
+			escaped = false
+		case string(character) == keyEscape:
+			escaped = true
+		case string(character) == KeySeparator:
+			values = append(values, builder.String())
+			builder.Reset()
+		default:
+			builder.WriteRune(character)
+		}
+	}
+
+	return append(values, builder.String())
+}
+
+// PluckRowId Pluck the row ids from the map. It works for any key columns.
+//
+//goland:noinspection GoUnusedExportedFunction
+func PluckRowId(valueMap map[Key]string) []RowId {
+	exists := make(map[RowId]bool)
+	r := make([]RowId, 0)
+	for mapKey := range valueMap {
+		rowId := mapKey.RowId()
+		if !exists[rowId] {
+			exists[rowId] = true
+			r = append(r, rowId)
+		}
+	}
+
+	SortRowIds(r)
+
+	return r
+}
+
+// SortRowIds Sort the row ids in ascending order.
+func SortRowIds(rowIds []RowId) {
+	sort.Slice(rowIds, func(i, j int) bool {
+		return rowIds[i].Less(rowIds[j])
+	})
+}
+
+// keyColumns Get the key columns of the table defined by the schema.
+func (s *Schema) keyColumns() []string {
+	if s == nil || len(s.KeyColumns) == 0 {
+		return DefaultKeyColumns
+	}
+
+	return s.KeyColumns
+}
+
+// isNumericKey Check if the rows are identified by Id instead of Code.
+// A single key column is numeric when it is declared as int, or when it is the undeclared id column.
+func (s *Schema) isNumericKey() bool {
+	keyColumns := s.keyColumns()
+	if len(keyColumns) != 1 {
+		return false
+	}
+	if s != nil {
+		if column, ok := s.Column(keyColumns[0]); ok {
+			return column.Type == TypeInt
+		}
+	}
+
+	return keyColumns[0] == "id"
+}
@@ -0,0 +1,138 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRowId_Less(t *testing.T) {
+	tests := []struct {
+		name  string
+		rowId RowId
+		other RowId
+		want  bool
+	}{
+		{name: "Less1", rowId: RowId{Id: 2}, other: RowId{Id: 10}, want: true},
+		{name: "Less2", rowId: RowId{Code: "1:2"}, other: RowId{Code: "1:10"}, want: true},
+		{name: "Less3", rowId: RowId{Code: "1:10"}, other: RowId{Code: "1:2"}, want: false},
+		{name: "Less4", rowId: RowId{Code: "bow"}, other: RowId{Code: "sword"}, want: true},
+		{name: "Less5", rowId: RowId{Code: "1"}, other: RowId{Code: "1:1"}, want: true},
+		{name: "Less6", rowId: RowId{Code: "01"}, other: RowId{Code: "1"}, want: true},
+		{name: "Less7", rowId: RowId{Code: "1"}, other: RowId{Code: "01"}, want: false},
+		{name: "Less8", rowId: RowId{Code: "10"}, other: RowId{Code: "1a"}, want: true},
+		{name: "Less9", rowId: RowId{Code: "1a"}, other: RowId{Code: "2"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rowId.Less(tt.other); got != tt.want {
+				t.Errorf("Less() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPluckRowId(t *testing.T) {
+	valueMap := map[Key]string{
+		{Code: "1:10", Key: "quest_id"}: "1",
+		{Code: "1:10", Key: "step"}:     "10",
+		{Code: "1:2", Key: "quest_id"}:  "1",
+		{Code: "1:2", Key: "step"}:      "2",
+	}
+	want := []RowId{{Code: "1:2"}, {Code: "1:10"}}
+	if got := PluckRowId(valueMap); !reflect.DeepEqual(got, want) {
+		t.Errorf("PluckRowId() = %v, want %v", got, want)
+	}
+}
+
+func TestRowId_Values(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		code   string
+	}{
+		{name: "Values1", values: []string{"1", "10"}, code: "1:10"},
+		{name: "Values2", values: []string{"x:y", "z"}, code: `x\:y:z`},
+		{name: "Values3", values: []string{"x", "y:z"}, code: `x:y\:z`},
+		{name: "Values4", values: []string{`a\`, ""}, code: `a\\:`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rowId := codeRowId(tt.values...)
+			if rowId.Code != tt.code {
+				t.Errorf("codeRowId() = %v, want %v", rowId.Code, tt.code)
+			}
+			if got := rowId.Values(); !reflect.DeepEqual(got, tt.values) {
+				t.Errorf("Values() = %v, want %v", got, tt.values)
+			}
+		})
+	}
+}
+
+func TestConvertMap_CompositeKeyWithSeparator(t *testing.T) {
+	schema := &Schema{KeyColumns: []string{"group", "name"}}
+	rows := [][]string{
+		{"group", "name", "value"},
+		{"x:y", "z", "1"},
+		{"x", "y:z", "2"},
+	}
+	f, err := convertMap(rows, nil, "items.csv", schema)
+	if err != nil {
+		t.Errorf("convertMap() error = %v", err)
+		return
+	}
+	want := []RowId{codeRowId("x", "y:z"), codeRowId("x:y", "z")}
+	if got := f.RowIds(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RowIds() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadByVersionRange_CompositeKey(t *testing.T) {
+	m := NewTabular("quest_steps", "csv", map[Key]string{}, false)
+	m.SetSchema(&Schema{KeyColumns: []string{"quest_id", "step"}})
+
+	if err := m.LoadByVersionRange("./testdata/composite", "", ""); err != nil {
+		t.Errorf("LoadByVersionRange() error = %v", err)
+		return
+	}
+	want := [][]string{
+		{"quest_id", "step", "reward"},
+		{"1", "1", "100"},
+		{"1", "2", "250"},
+		{"1", "10", "300"},
+		{"2", "2", "500"},
+	}
+	if got := m.ToRows(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToRows() = %v, want %v", got, want)
+	}
+
+	m = NewTabular("quest_steps", "csv", map[Key]string{}, false)
+	m.SetSchema(&Schema{KeyColumns: []string{"quest_id", "step"}})
+	if err := m.LoadByDirectoryPath("./testdata/composite_error"); err == nil {
+		t.Errorf("LoadByDirectoryPath() error = nil, want error")
+	}
+}
+
+func TestLoadMapWithSchema_StringKey(t *testing.T) {
+	schema := &Schema{
+		Columns:    []Column{{Name: "code", Type: TypeString}},
+		KeyColumns: []string{"code"},
+	}
+	got, err := LoadMapWithSchema("./testdata/string_key/insert/weapons.csv", schema)
+	if err != nil {
+		t.Errorf("LoadMapWithSchema() error = %v", err)
+		return
+	}
+	want := map[Key]string{
+		{Code: "sword", Key: "code"}: "sword",
+		{Code: "sword", Key: "name"}: "Sword",
+		{Code: "bow", Key: "code"}:   "bow",
+		{Code: "bow", Key: "name"}:   "Bow",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadMapWithSchema() got = %v, want %v", got, want)
+	}
+
+	if _, err = LoadMap("./testdata/string_key/insert/weapons.csv"); err == nil {
+		t.Errorf("LoadMap() error = nil, want error")
+	}
+}
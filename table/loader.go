@@ -0,0 +1,164 @@
+package table
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/stepupdream/go-support-tool/console"
+)
+
+// Loader loads many tables concurrently with a bounded number of goroutines.
+// The context of a load is checked before each table and between versions, but not inside a version directory.
+// A version directory that has started is loaded to the end, since it is applied all-or-nothing.
+type Loader struct {
+	ExtensionName  string
+	IsPartialMatch bool
+	// Workers is the maximum number of tables loaded at the same time. If 0 or less, runtime.NumCPU is used.
+	Workers int
+	// Schemas is the schema of each table by name. A table without a schema is loaded without validation.
+	Schemas    map[string]*Schema
+	UpdateMode UpdateMode
+	ErrorMode  ErrorMode
+	// Operations is the operations that the tables apply. If nil, DefaultOperationRegistry is used.
+	Operations *OperationRegistry
+	// ShowProgress reports the number of loaded tables through console.StartProgressBar.
+	ShowProgress bool
+}
+
+// TableError is returned when the load of a table fails.
+type TableError struct {
+	Table string
+	Err   error
+}
+
+// Error returns the error message including the failed table.
+func (e *TableError) Error() string {
+	return "Failed to load table : " + e.Table + " : " + e.Err.Error()
+}
+
+// Unwrap returns the error that caused the failure.
+func (e *TableError) Unwrap() error {
+	return e.Err
+}
+
+// TableErrors is the list of the tables that failed to load, in the order of the table names.
+type TableErrors []*TableError
+
+// Error returns the messages of all errors, one per line.
+func (e TableErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, tableError := range e {
+		messages = append(messages, tableError.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// NewLoader Create a new Loader.
+//
+//goland:noinspection GoUnusedExportedFunction
+func NewLoader(extensionName string, isPartialMatch bool) *Loader {
+	return &Loader{
+		ExtensionName:  extensionName,
+		IsPartialMatch: isPartialMatch,
+	}
+}
+
+// LoadByDirectoryPath Load the specified tables from the directory path concurrently.
+// See MasterData.LoadByDirectoryPath for the layout of the directory.
+func (l *Loader) LoadByDirectoryPath(ctx context.Context, names []string, directoryPath string) (map[string]*MasterData, error) {
+	return l.load(ctx, names, func(m *MasterData) error {
+		return m.LoadByDirectoryPath(directoryPath)
+	})
+}
+
+// LoadByVersionRange Load the specified tables from the version directories concurrently.
+// See MasterData.LoadByVersionRange for the versions that are applied.
+// The context is also checked between the versions of a table, so a cancel stops a long range after the current version.
+func (l *Loader) LoadByVersionRange(ctx context.Context, names []string, rootDirectoryPath string, fromVersion string, toVersion string) (map[string]*MasterData, error) {
+	return l.load(ctx, names, func(m *MasterData) error {
+		return m.loadByVersionRange(ctx, rootDirectoryPath, fromVersion, toVersion)
+	})
+}
+
+// load Create a table for each name and apply loadTable to them with the workers.
+// It returns every table by name, or the errors of all tables that failed.
+// When the context is canceled, the tables that have not started are skipped and the error of the context is returned.
+func (l *Loader) load(ctx context.Context, names []string, loadTable func(m *MasterData) error) (map[string]*MasterData, error) {
+	workers := l.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	tables := make([]*MasterData, len(names))
+	tableErrors := make([]error, len(names))
+	indexes := make(chan int)
+
+	var wait sync.WaitGroup
+	var bar *pb.ProgressBar
+	if l.ShowProgress {
+		bar = console.StartProgressBar(len(names))
+		defer bar.Finish()
+	}
+
+	for i := 0; i < workers; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for index := range indexes {
+				tables[index] = l.newTable(names[index])
+				tableErrors[index] = loadTable(tables[index])
+				if bar != nil {
+					bar.Increment()
+				}
+			}
+		}()
+	}
+
+	canceled := false
+	for index := range names {
+		if canceled {
+			break
+		}
+		select {
+		case indexes <- index:
+		case <-ctx.Done():
+			canceled = true
+		}
+	}
+	close(indexes)
+	wait.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var errs TableErrors
+	r := make(map[string]*MasterData, len(names))
+	for index, tableName := range names {
+		if tableErrors[index] != nil {
+			errs = append(errs, &TableError{Table: tableName, Err: tableErrors[index]})
+			continue
+		}
+		r[tableName] = tables[index]
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return r, nil
+}
+
+// newTable Create an empty table with the settings of the loader.
+func (l *Loader) newTable(name string) *MasterData {
+	m := NewTabular(name, l.ExtensionName, make(map[Key]string), l.IsPartialMatch)
+	m.SetSchema(l.Schemas[name])
+	m.SetUpdateMode(l.UpdateMode)
+	m.SetErrorMode(l.ErrorMode)
+	m.SetOperationRegistry(l.Operations)
+
+	return m
+}
@@ -0,0 +1,73 @@
+package table
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLoader_LoadByDirectoryPath(t *testing.T) {
+	loader := NewLoader("csv", false)
+	loader.Workers = 2
+
+	got, err := loader.LoadByDirectoryPath(context.Background(), []string{"items", "weapons"}, "./testdata/loader")
+	if err != nil {
+		t.Errorf("LoadByDirectoryPath() error = %v", err)
+		return
+	}
+	want := map[string]map[Key]string{
+		"items": {
+			{Id: 1, Key: "id"}:   "1",
+			{Id: 1, Key: "name"}: "sword",
+			{Id: 2, Key: "id"}:   "2",
+			{Id: 2, Key: "name"}: "shield",
+		},
+		"weapons": {
+			{Id: 1, Key: "id"}:    "1",
+			{Id: 1, Key: "power"}: "10",
+		},
+	}
+	if len(got) != len(want) {
+		t.Errorf("LoadByDirectoryPath() got %v tables, want %v", len(got), len(want))
+	}
+	for tableName, rows := range want {
+		if got[tableName] == nil || !reflect.DeepEqual(got[tableName].Rows, rows) {
+			t.Errorf("LoadByDirectoryPath() got = %v, want %v", got[tableName], rows)
+		}
+	}
+}
+
+func TestLoader_LoadByDirectoryPath_Error(t *testing.T) {
+	loader := NewLoader("csv", false)
+
+	_, err := loader.LoadByDirectoryPath(context.Background(), []string{"items", "weapons"}, "./testdata/loader_error")
+	var tableErrors TableErrors
+	if !errors.As(err, &tableErrors) || len(tableErrors) != 1 || tableErrors[0].Table != "weapons" {
+		t.Errorf("LoadByDirectoryPath() error = %v, want the error of weapons", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = loader.LoadByDirectoryPath(ctx, []string{"items", "weapons"}, "./testdata/loader"); !errors.Is(err, context.Canceled) {
+		t.Errorf("LoadByDirectoryPath() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestLoader_LoadByVersionRange_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	m := NewTabular("samples", "csv", make(map[Key]string), false)
+	if err := m.loadByVersionRange(ctx, "./testdata/versions", "", ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("loadByVersionRange() error = %v, want %v", err, context.Canceled)
+	}
+	if len(m.Rows) != 0 {
+		t.Errorf("loadByVersionRange() got = %v, want no rows", m.Rows)
+	}
+
+	loader := NewLoader("csv", false)
+	if _, err := loader.LoadByVersionRange(ctx, []string{"samples"}, "./testdata/versions", "", ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("LoadByVersionRange() error = %v, want %v", err, context.Canceled)
+	}
+}
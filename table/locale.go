@@ -0,0 +1,228 @@
+package table
+
+import (
+	"strings"
+
+	"github.com/stepupdream/go-support-tool/array"
+	"github.com/stepupdream/go-support-tool/delimited"
+)
+
+// LocaleSeparator is the separator between the name of a column and its locale. (ex. name_ja)
+const LocaleSeparator = "_"
+
+// Localization is the declaration of the localized columns of a table.
+// A column whose name ends with LocaleSeparator and a locale is the value of the logical column for the locale.
+// (ex. name_ja and name_en are the logical column name)
+type Localization struct {
+	// Locales is the locales of the localized columns. (ex. ja, en, zh)
+	Locales []string
+	// DefaultLocale is the locale tried last when the value of the requested locale is missing.
+	DefaultLocale string
+	// Fallbacks is the locales tried in order before DefaultLocale for each locale. (ex. zh -> en)
+	// The fallbacks of a fallback are followed, so with zh -> en and en -> ja, zh tries zh, en and ja in order.
+	// A locale that is already tried is skipped, so a cycle of fallbacks ends.
+	Fallbacks map[string][]string
+}
+
+// MissingTranslation is a value of a localized column that is missing or empty.
+type MissingTranslation struct {
+	Locale string
+	Id     RowId
+	Column string
+	// Source is the value of the column in the default locale, or its fallback, that the translator translates.
+	Source string
+}
+
+// SetLocalization Set the declaration of the localized columns.
+func (m *MasterData) SetLocalization(localization *Localization) {
+	m.localization = localization
+}
+
+// LocalizedColumns Get the logical columns that have a localized column, in the order of the columns.
+func (m *MasterData) LocalizedColumns() []string {
+	var r []string
+	for _, column := range m.Columns() {
+		logicalColumn, _, ok := m.localization.parseColumn(column)
+		if ok && !array.Contains(r, logicalColumn) {
+			r = append(r, logicalColumn)
+		}
+	}
+
+	return r
+}
+
+// LocalizedValue Get the value of the logical column of the row for the locale.
+// If the value is missing or empty, the fallbacks of the locale and the default locale are tried in order.
+func (m *MasterData) LocalizedValue(id RowId, column string, locale string) (string, bool) {
+	for _, candidate := range m.localization.chain(locale) {
+		if value := m.Rows[id.Key(column+LocaleSeparator+candidate)]; value != "" {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// Localize Create a snapshot of the table for the locale.
+// The localized columns are replaced by their logical column, which holds the value resolved by LocalizedValue.
+// The schema of the snapshot declares the logical column instead of the localized columns. (see localizeSchema)
+func (m *MasterData) Localize(locale string) *MasterData {
+	snapshot := &MasterData{
+		name:           m.name,
+		isPartialMatch: m.isPartialMatch,
+		extension:      m.extension,
+		updateMode:     m.updateMode,
+		errorMode:      m.errorMode,
+		schema:         m.localization.localizeSchema(m.schema),
+		operations:     m.operations,
+		Rows:           make(map[Key]string),
+	}
+
+	for _, column := range m.Columns() {
+		if logicalColumn, _, ok := m.localization.parseColumn(column); ok {
+			column = logicalColumn
+		}
+		if !array.Contains(snapshot.columns, column) {
+			snapshot.columns = append(snapshot.columns, column)
+		}
+	}
+
+	logicalColumns := m.LocalizedColumns()
+	for key, value := range m.Rows {
+		if _, _, ok := m.localization.parseColumn(key.Key); !ok {
+			snapshot.Rows[key] = value
+		}
+	}
+	for _, id := range m.RowIds() {
+		for _, column := range logicalColumns {
+			if value, ok := m.LocalizedValue(id, column, locale); ok {
+				snapshot.Rows[id.Key(column)] = value
+			}
+		}
+	}
+
+	return snapshot
+}
+
+// MissingTranslations Get the values of the localized columns that are missing or empty,
+// ordered by the locale in the order of Locales, the id and the column.
+// A logical column of a row whose value is missing in every locale is not reported, since there is nothing to translate.
+func (m *MasterData) MissingTranslations() []MissingTranslation {
+	if m.localization == nil {
+		return nil
+	}
+
+	var r []MissingTranslation
+	logicalColumns := m.LocalizedColumns()
+	for _, locale := range m.localization.Locales {
+		for _, id := range m.RowIds() {
+			for _, column := range logicalColumns {
+				if m.Rows[id.Key(column+LocaleSeparator+locale)] != "" {
+					continue
+				}
+				source, ok := m.LocalizedValue(id, column, m.localization.DefaultLocale)
+				if !ok {
+					source, ok = m.anyLocalizedValue(id, column)
+				}
+				if ok {
+					r = append(r, MissingTranslation{Locale: locale, Id: id, Column: column, Source: source})
+				}
+			}
+		}
+	}
+
+	return r
+}
+
+// ExportMissingTranslations Write the missing translations to the specified file as the work list of the translators.
+// The columns are locale, id, column and source.
+func (m *MasterData) ExportMissingTranslations(path string) error {
+	rows := [][]string{{"locale", "id", "column", "source"}}
+	for _, missing := range m.MissingTranslations() {
+		rows = append(rows, []string{missing.Locale, missing.Id.String(), missing.Column, missing.Source})
+	}
+
+	return delimited.CreateNewFile(path, rows)
+}
+
+// anyLocalizedValue Get the value of the logical column of the row in the first locale that has it.
+func (m *MasterData) anyLocalizedValue(id RowId, column string) (string, bool) {
+	for _, locale := range m.localization.Locales {
+		if value := m.Rows[id.Key(column+LocaleSeparator+locale)]; value != "" {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// parseColumn Split the localized column into the logical column and the locale.
+// The longest locale is used when several locales match. (ex. zh_tw before tw)
+func (l *Localization) parseColumn(column string) (logicalColumn string, locale string, ok bool) {
+	if l == nil {
+		return "", "", false
+	}
+
+	for _, candidate := range l.Locales {
+		suffix := LocaleSeparator + candidate
+		if strings.HasSuffix(column, suffix) && len(column) > len(suffix) && len(candidate) > len(locale) {
+			logicalColumn, locale, ok = strings.TrimSuffix(column, suffix), candidate, true
+		}
+	}
+
+	return logicalColumn, locale, ok
+}
+
+// chain Get the locales tried in order for the locale.
+// The fallbacks are followed depth first, skipping the locales already tried, and DefaultLocale is the last.
+func (l *Localization) chain(locale string) []string {
+	if l == nil {
+		return nil
+	}
+
+	var r []string
+	tried := make(map[string]bool)
+	var follow func(locale string)
+	follow = func(locale string) {
+		if tried[locale] {
+			return
+		}
+		tried[locale] = true
+		r = append(r, locale)
+		for _, fallback := range l.Fallbacks[locale] {
+			follow(fallback)
+		}
+	}
+	follow(locale)
+	if l.DefaultLocale != "" {
+		follow(l.DefaultLocale)
+	}
+
+	return r
+}
+
+// localizeSchema Get the schema of a localized snapshot.
+// The localized columns are replaced by their logical column, which takes the definition of the first localized column
+// and is nullable, since a value may be missing in every locale.
+func (l *Localization) localizeSchema(schema *Schema) *Schema {
+	if l == nil || schema == nil {
+		return schema
+	}
+
+	r := *schema
+	r.Columns = make([]Column, 0, len(schema.Columns))
+	added := make(map[string]bool)
+	for _, column := range schema.Columns {
+		if logicalColumn, _, ok := l.parseColumn(column.Name); ok {
+			if added[logicalColumn] {
+				continue
+			}
+			added[logicalColumn] = true
+			column.Name = logicalColumn
+			column.Nullable = true
+		}
+		r.Columns = append(r.Columns, column)
+	}
+
+	return &r
+}
@@ -0,0 +1,130 @@
+package table
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/stepupdream/go-support-tool/delimited"
+)
+
+func testLocaleTable() *MasterData {
+	m := NewTabular("items", "csv", map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name_ja"}: "剣", {Id: 1, Key: "name_en"}: "Sword", {Id: 1, Key: "name_zh_tw"}: "劍",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name_ja"}: "盾", {Id: 2, Key: "name_en"}: "", {Id: 2, Key: "price"}: "20",
+		{Id: 3, Key: "id"}: "3", {Id: 3, Key: "name_ja"}: "",
+	}, false)
+	m.SetColumns([]string{"id", "name_ja", "name_en", "name_zh_tw", "price"})
+	m.SetLocalization(&Localization{
+		Locales:       []string{"ja", "en", "zh_tw"},
+		DefaultLocale: "ja",
+		Fallbacks:     map[string][]string{"zh_tw": {"en"}},
+	})
+
+	return m
+}
+
+func TestMasterData_LocalizedValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     RowId
+		locale string
+		want   string
+		wantOk bool
+	}{
+		{name: "LocalizedValue1", id: RowId{Id: 1}, locale: "zh_tw", want: "劍", wantOk: true},
+		{name: "LocalizedValue2", id: RowId{Id: 2}, locale: "zh_tw", want: "盾", wantOk: true},
+		{name: "LocalizedValue3", id: RowId{Id: 1}, locale: "fr", want: "剣", wantOk: true},
+		{name: "LocalizedValue4", id: RowId{Id: 3}, locale: "en", want: "", wantOk: false},
+	}
+	m := testLocaleTable()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := m.LocalizedValue(tt.id, "name", tt.locale)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("LocalizedValue() = %v %v, want %v %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+
+	if got, want := m.LocalizedColumns(), []string{"name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LocalizedColumns() = %v, want %v", got, want)
+	}
+}
+
+func TestMasterData_Localize(t *testing.T) {
+	want := [][]string{
+		{"id", "name", "price"},
+		{"1", "Sword", ""},
+		{"2", "盾", "20"},
+		{"3", "", ""},
+	}
+	if got := testLocaleTable().Localize("en").ToRows(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Localize() = %v, want %v", got, want)
+	}
+}
+
+func TestMasterData_MissingTranslations(t *testing.T) {
+	m := testLocaleTable()
+	want := []MissingTranslation{
+		{Locale: "en", Id: RowId{Id: 2}, Column: "name", Source: "盾"},
+		{Locale: "zh_tw", Id: RowId{Id: 2}, Column: "name", Source: "盾"},
+	}
+	if got := m.MissingTranslations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("MissingTranslations() = %v, want %v", got, want)
+	}
+
+	path := t.TempDir() + string(os.PathSeparator) + "missing.csv"
+	if err := m.ExportMissingTranslations(path); err != nil {
+		t.Errorf("ExportMissingTranslations() error = %v", err)
+		return
+	}
+	rows, err := delimited.Load(path, false, false)
+	if err != nil || len(rows) != 3 || !reflect.DeepEqual(rows[1], []string{"en", "2", "name", "盾"}) {
+		t.Errorf("ExportMissingTranslations() got = %v, error = %v", rows, err)
+	}
+}
+
+func TestLocalization_chain(t *testing.T) {
+	l := &Localization{
+		DefaultLocale: "ja",
+		Fallbacks:     map[string][]string{"zh": {"en", "fr"}, "en": {"ko"}, "ko": {"zh"}},
+	}
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{locale: "zh", want: []string{"zh", "en", "ko", "fr", "ja"}},
+		{locale: "ko", want: []string{"ko", "zh", "en", "fr", "ja"}},
+		{locale: "ja", want: []string{"ja"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			if got := l.chain(tt.locale); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMasterData_Localize_Schema(t *testing.T) {
+	m := testLocaleTable()
+	m.SetSchema(&Schema{Columns: []Column{
+		{Name: "id", Type: TypeInt},
+		{Name: "name_ja", Type: TypeString},
+		{Name: "name_en", Type: TypeString},
+		{Name: "price", Type: TypeInt, Nullable: true},
+	}})
+
+	want := []Column{
+		{Name: "id", Type: TypeInt},
+		{Name: "name", Type: TypeString, Nullable: true},
+		{Name: "price", Type: TypeInt, Nullable: true},
+	}
+	if got := m.Localize("en").Schema().Columns; !reflect.DeepEqual(got, want) {
+		t.Errorf("Localize() schema = %v, want %v", got, want)
+	}
+	if len(m.Schema().Columns) != 4 {
+		t.Errorf("Localize() changed the schema of the table : %v", m.Schema().Columns)
+	}
+}
@@ -0,0 +1,230 @@
+package table
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/go-support-tool/delimited"
+	"github.com/stepupdream/go-support-tool/directory"
+	"github.com/stepupdream/go-support-tool/file"
+)
+
+// ManifestColumnSeparator is the separator of the column names in the manifest file.
+const ManifestColumnSeparator = "|"
+
+// manifestHeaders is the header of the manifest file.
+var manifestHeaders = []string{"table", "hash", "rows", "columns"}
+
+// Hash Compute the SHA-256 hash of the values of the table as a hex string.
+// The rows are hashed in the order of the row id and the values of a row in the order of the column name,
+// so the hash does not depend on the order of the map or the columns. A missing value and an empty value are different.
+// The row ids are ordered by their raw values rather than by Less, so the hash depends only on the values of the table.
+func (m *MasterData) Hash() string {
+	ids := m.RowIds()
+	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].Id != ids[j].Id {
+			return ids[i].Id < ids[j].Id
+		}
+		return ids[i].Code < ids[j].Code
+	})
+
+	hash := sha256.New()
+	var buffer []byte
+	for _, id := range ids {
+		row := m.Row(id)
+		columns := make([]string, 0, len(row))
+		for column := range row {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+
+		buffer = binary.AppendVarint(buffer[:0], int64(id.Id))
+		buffer = appendHashString(buffer, id.Code)
+		buffer = binary.AppendUvarint(buffer, uint64(len(columns)))
+		for _, column := range columns {
+			buffer = appendHashString(buffer, column)
+			buffer = appendHashString(buffer, row[column])
+		}
+		hash.Write(buffer)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// appendHashString Append the string with its length, so that the boundaries of the strings are part of the hash.
+func appendHashString(buffer []byte, value string) []byte {
+	buffer = binary.AppendUvarint(buffer, uint64(len(value)))
+	return append(buffer, value...)
+}
+
+// ManifestEntry is the fingerprint of a table in a manifest.
+type ManifestEntry struct {
+	Table    string
+	Hash     string
+	RowCount int
+	Columns  []string
+}
+
+// Manifest is the fingerprints of the tables after a version is applied.
+type Manifest struct {
+	Version string
+	Entries []ManifestEntry
+}
+
+// ManifestDrift is a difference between a manifest and the tables built from the sources.
+type ManifestDrift struct {
+	Version string
+	Table   string
+	// Field is the field that differs. (hash, rows, columns, or table when the table exists on only one side)
+	Field    string
+	Expected string
+	Actual   string
+}
+
+// String returns the text of the drift used in messages.
+func (d ManifestDrift) String() string {
+	return "Manifest drift : version : " + d.Version + " table : " + d.Table + " " + d.Field + " : " + d.Expected + " -> " + d.Actual
+}
+
+// NewManifest Create the manifest of the tables, in the order of the table name.
+//
+//goland:noinspection GoUnusedExportedFunction
+func NewManifest(version string, tables map[string]*MasterData) *Manifest {
+	manifest := &Manifest{Version: version}
+	for tableName, m := range tables {
+		manifest.Entries = append(manifest.Entries, ManifestEntry{
+			Table:    tableName,
+			Hash:     m.Hash(),
+			RowCount: len(m.RowIds()),
+			Columns:  m.Columns(),
+		})
+	}
+	sort.Slice(manifest.Entries, func(i, j int) bool {
+		return manifest.Entries[i].Table < manifest.Entries[j].Table
+	})
+
+	return manifest
+}
+
+// LoadManifest Load the manifest file. The version is the base name of the file.
+//
+//goland:noinspection GoUnusedExportedFunction
+func LoadManifest(path string) (*Manifest, error) {
+	rows, err := delimited.Load(path, false, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 || strings.Join(rows[0], ",") != strings.Join(manifestHeaders, ",") {
+		return nil, errors.New("Not a manifest file : " + path)
+	}
+
+	manifest := &Manifest{Version: file.BaseFileName(path)}
+	for rowNumber, row := range rows[1:] {
+		if len(row) != len(manifestHeaders) {
+			return nil, errors.New("Invalid manifest row : " + path + " rowNumber : " + strconv.Itoa(rowNumber+1))
+		}
+		rowCount, err := strconv.Atoi(row[2])
+		if err != nil {
+			return nil, errors.New("Row count is not int : " + path + " rowNumber : " + strconv.Itoa(rowNumber+1))
+		}
+
+		entry := ManifestEntry{Table: row[0], Hash: row[1], RowCount: rowCount}
+		if row[3] != "" {
+			entry.Columns = strings.Split(row[3], ManifestColumnSeparator)
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+
+	return manifest, nil
+}
+
+// Write Write the manifest to the specified file.
+func (mf *Manifest) Write(path string) error {
+	rows := [][]string{manifestHeaders}
+	for _, entry := range mf.Entries {
+		rows = append(rows, []string{entry.Table, entry.Hash, strconv.Itoa(entry.RowCount), strings.Join(entry.Columns, ManifestColumnSeparator)})
+	}
+
+	return delimited.CreateNewFile(path, rows)
+}
+
+// Verify Compare the manifest with the manifest of the tables built from the sources.
+func (mf *Manifest) Verify(tables map[string]*MasterData) []ManifestDrift {
+	actual := NewManifest(mf.Version, tables)
+	actualEntries := make(map[string]ManifestEntry)
+	for _, entry := range actual.Entries {
+		actualEntries[entry.Table] = entry
+	}
+
+	var drifts []ManifestDrift
+	expectedTables := make(map[string]bool)
+	for _, expected := range mf.Entries {
+		expectedTables[expected.Table] = true
+		newDrift := func(field string, expectedValue string, actualValue string) ManifestDrift {
+			return ManifestDrift{Version: mf.Version, Table: expected.Table, Field: field, Expected: expectedValue, Actual: actualValue}
+		}
+
+		entry, ok := actualEntries[expected.Table]
+		if !ok {
+			drifts = append(drifts, newDrift("table", "exists", "missing"))
+			continue
+		}
+		if entry.Hash != expected.Hash {
+			drifts = append(drifts, newDrift("hash", expected.Hash, entry.Hash))
+		}
+		if entry.RowCount != expected.RowCount {
+			drifts = append(drifts, newDrift("rows", strconv.Itoa(expected.RowCount), strconv.Itoa(entry.RowCount)))
+		}
+		expectedColumns := strings.Join(expected.Columns, ManifestColumnSeparator)
+		if actualColumns := strings.Join(entry.Columns, ManifestColumnSeparator); actualColumns != expectedColumns {
+			drifts = append(drifts, newDrift("columns", expectedColumns, actualColumns))
+		}
+	}
+	for _, entry := range actual.Entries {
+		if !expectedTables[entry.Table] {
+			drifts = append(drifts, ManifestDrift{Version: mf.Version, Table: entry.Table, Field: "table", Expected: "missing", Actual: "exists"})
+		}
+	}
+
+	return drifts
+}
+
+// WriteManifests Load the version directories in order, and write the manifest of the tables after each version
+// to the manifest directory as <version>.csv.
+func (r *TableRegistry) WriteManifests(rootDirectoryPath string, manifestDirectoryPath string, fromVersion string, toVersion string) error {
+	if err := directory.Create(manifestDirectoryPath, false); err != nil {
+		return err
+	}
+
+	return r.eachVersion(rootDirectoryPath, fromVersion, toVersion, func(version string) error {
+		return NewManifest(version, r.Tables).Write(manifestPath(manifestDirectoryPath, version))
+	})
+}
+
+// VerifyManifests Load the version directories in order, and compare the tables after each version with its manifest
+// in the manifest directory. A version without a manifest file is an error.
+func (r *TableRegistry) VerifyManifests(rootDirectoryPath string, manifestDirectoryPath string, fromVersion string, toVersion string) ([]ManifestDrift, error) {
+	var drifts []ManifestDrift
+	err := r.eachVersion(rootDirectoryPath, fromVersion, toVersion, func(version string) error {
+		manifest, err := LoadManifest(manifestPath(manifestDirectoryPath, version))
+		if err != nil {
+			return err
+		}
+		drifts = append(drifts, manifest.Verify(r.Tables)...)
+
+		return nil
+	})
+
+	return drifts, err
+}
+
+// manifestPath Get the path of the manifest file of the version.
+func manifestPath(manifestDirectoryPath string, version string) string {
+	return manifestDirectoryPath + string(os.PathSeparator) + version + ".csv"
+}
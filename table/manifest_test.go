@@ -0,0 +1,88 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMasterData_Hash(t *testing.T) {
+	base := NewTabular("items", "csv", testJournalRows(), false)
+	reordered := NewTabular("items", "csv", testJournalRows(), false)
+	reordered.SetColumns([]string{"level", "sample", "id"})
+	if base.Hash() != reordered.Hash() {
+		t.Errorf("Hash() depends on the order of the columns")
+	}
+
+	changedRows := testJournalRows()
+	changedRows[Key{Id: 2, Key: "sample"}] = "ccc"
+	emptyRows := testJournalRows()
+	emptyRows[Key{Id: 2, Key: "memo"}] = ""
+	absentRows := testJournalRows()
+	delete(absentRows, Key{Id: 2, Key: "level"})
+	for _, rows := range []map[Key]string{changedRows, emptyRows, absentRows} {
+		if NewTabular("items", "csv", rows, false).Hash() == base.Hash() {
+			t.Errorf("Hash() is the same for different rows : %v", rows)
+		}
+	}
+}
+
+func TestMasterData_Hash_EquivalentCodes(t *testing.T) {
+	schema := &Schema{KeyColumns: []string{"code"}}
+	newTable := func() *MasterData {
+		rows := make(map[Key]string)
+		for _, code := range []string{"01", "1", "001", "a", "b"} {
+			rows[codeRowId(code).Key("code")] = code
+		}
+		m := NewTabular("items", "csv", rows, false)
+		m.SetSchema(schema)
+		return m
+	}
+
+	want := newTable().Hash()
+	for i := 0; i < 200; i++ {
+		if got := newTable().Hash(); got != want {
+			t.Errorf("Hash() = %v, want %v", got, want)
+			return
+		}
+	}
+}
+
+func TestTableRegistry_VerifyManifests(t *testing.T) {
+	manifestDirectoryPath := t.TempDir()
+	if err := NewTableRegistry("csv", true).WriteManifests("./testdata/registry", manifestDirectoryPath, "", ""); err != nil {
+		t.Errorf("WriteManifests() error = %v", err)
+		return
+	}
+
+	manifest, err := LoadManifest(manifestPath(manifestDirectoryPath, "1_0_0_0"))
+	if err != nil {
+		t.Errorf("LoadManifest() error = %v", err)
+		return
+	}
+	if len(manifest.Entries) != 1 || manifest.Entries[0].RowCount != 2 || !reflect.DeepEqual(manifest.Entries[0].Columns, []string{"id", "name"}) {
+		t.Errorf("LoadManifest() got = %v", manifest)
+	}
+
+	drifts, err := NewTableRegistry("csv", true).VerifyManifests("./testdata/registry", manifestDirectoryPath, "", "")
+	if err != nil || len(drifts) != 0 {
+		t.Errorf("VerifyManifests() = %v, error = %v", drifts, err)
+	}
+
+	manifest.Entries[0].Hash = "changed"
+	manifest.Entries = append(manifest.Entries, ManifestEntry{Table: "weapons"})
+	if err = manifest.Write(manifestPath(manifestDirectoryPath, "1_0_0_0")); err != nil {
+		t.Fatal(err)
+	}
+	drifts, err = NewTableRegistry("csv", true).VerifyManifests("./testdata/registry", manifestDirectoryPath, "", "")
+	if err != nil {
+		t.Errorf("VerifyManifests() error = %v", err)
+		return
+	}
+	var fields []string
+	for _, drift := range drifts {
+		fields = append(fields, drift.Version+" "+drift.Table+" "+drift.Field)
+	}
+	if want := []string{"1_0_0_0 items hash", "1_0_0_0 weapons table"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("VerifyManifests() = %v, want %v", fields, want)
+	}
+}
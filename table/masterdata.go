@@ -4,7 +4,6 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -13,11 +12,33 @@ import (
 	"github.com/stepupdream/go-support-tool/file"
 )
 
+// UpdateMode decides how the files in the update directory are applied.
+type UpdateMode int
+
+const (
+	// UpdateModeOverwrite overwrites the columns in the update file. A column that the table does not have is added.
+	UpdateModeOverwrite UpdateMode = iota
+	// UpdateModePartial overwrites only the columns in the update file, and the other columns keep their current values.
+	// The update file can hold only the id and the changed columns, and a column that the table does not have is an error.
+	UpdateModePartial
+)
+
 // MasterData is a struct used to represent tabular data.
+// Rows is the cell view of the values. Change it through SetValue and RemoveValue, or call Reindex after changing it directly.
 type MasterData struct {
 	name           string
 	isPartialMatch bool
 	extension      string
+	updateMode     UpdateMode
+	errorMode      ErrorMode
+	schema         *Schema
+	columns        []string
+	provenance     map[Key]Provenance
+	journal        *journal
+	changes        map[Key]bool
+	store          *rowStore
+	operations     *OperationRegistry
+	localization   *Localization
 	Rows           map[Key]string
 }
 
@@ -35,69 +56,146 @@ func NewTabular(name string, extensionName string, rows map[Key]string, isPartia
 	}
 }
 
+// SetSchema Set the schema used to check the values of the loaded files.
+func (m *MasterData) SetSchema(schema *Schema) {
+	m.schema = schema
+}
+
+// Schema Get the schema of the table. If no schema is set, return nil.
+func (m *MasterData) Schema() *Schema {
+	return m.schema
+}
+
+// SetUpdateMode Set how the files in the update directory are applied.
+func (m *MasterData) SetUpdateMode(updateMode UpdateMode) {
+	m.updateMode = updateMode
+}
+
 // LoadByDirectoryPath Load the specified directory path.
-// The directory path must be the path to the directory containing the insert, update, and delete directories.
+// The directory path must be the path to the directory containing the operation directories. (see OperationRegistry)
+// The load is all-or-nothing. If an error occurs, the table is restored to the state before the call.
 func (m *MasterData) LoadByDirectoryPath(directoryPath string) error {
-	// Avoid immediately UPDATING an INSET record within the same version (since it is an unintended update).
-	loadTypes := []string{"delete", "update", "insert"}
-	pathSeparator := string(os.PathSeparator)
-
-	if !directoryExists(directoryPath, loadTypes) {
-		return errors.New("Neither insert/update/delete directories were found : " + directoryPath)
+	m.begin()
+	if err := m.load(directoryPath); err != nil {
+		m.rollback()
+		return err
 	}
+	m.commit()
 
-	var editIdsAll []int
-	for _, loadType := range loadTypes {
-		loadTypePath := directoryPath + pathSeparator + loadType + pathSeparator
-		if !directory.Exist(loadTypePath) {
-			continue
-		}
+	return nil
+}
+
+// ValidateDirectoryPath Check that the specified directory path can be loaded on the current table, without changing it.
+func (m *MasterData) ValidateDirectoryPath(directoryPath string) error {
+	m.begin()
+	err := m.load(directoryPath)
+	m.rollback()
 
-		filePaths, err := directory.GetFilePathRecursive(loadTypePath, []string{m.extension})
+	return err
+}
+
+// load Apply the operation directories of the specified directory path in the order of their priority.
+// In ErrorModeCollect, the files after a problem are still checked so that every problem is reported at once.
+func (m *MasterData) load(directoryPath string) error {
+	operations := m.operationRegistry().Operations()
+	names := operationNames(operations)
+	if !directoryExists(directoryPath, names) {
+		return errors.New("Neither " + strings.Join(names, "/") + " directories were found : " + directoryPath)
+	}
+
+	// Detect errors such as duplicate IDs for insert and update. (see OperationContext.CheckUnique)
+	editedIds := make(map[RowId]bool)
+	version := filepath.Base(directoryPath)
+	var validationErrors ValidationErrors
+	for _, operation := range operations {
+		filePaths, err := m.editFilePaths(directoryPath, operation.Name())
 		if err != nil {
 			return err
 		}
 
-		for _, filePath := range filePaths {
-			if !((m.isPartialMatch && strings.HasPrefix(file.BaseFileName(filePath), m.name)) || (m.name == file.BaseFileName(filePath))) {
+		for fileIndex, filePath := range filePaths {
+			context := OperationContext{Version: version, FileIndex: fileIndex, editedIds: editedIds}
+			var editFile *EditFile
+			editFile, err = loadFile(filePath, m.schema)
+			if err == nil {
+				err = operation.Check(m, editFile, context)
+			}
+			if err == nil {
+				m.trackChanges()
+				err = operation.Apply(m, editFile, context)
+			}
+			changes := m.untrackChanges()
+
+			var fileErrors ValidationErrors
+			if errors.As(err, &fileErrors) {
+				if m.errorMode == ErrorModeFailFast {
+					return fileErrors[:1]
+				}
+				validationErrors = append(validationErrors, fileErrors...)
 				continue
 			}
-
-			var editMap map[Key]string
-			editMap, err = LoadMap(filePath)
 			if err != nil {
 				return err
 			}
 
-			editIds := PluckId(editMap)
-			editIdsAll = append(editIdsAll, editIds...)
-
-			switch loadType {
-			case "insert":
-				err = m.insert(editMap, filePath)
-			case "update":
-				err = m.update(editMap, filePath)
-			case "delete":
-				err = m.delete(editMap, filePath)
-			}
-
-			if err != nil {
-				return err
+			if m.hasValueOf(editFile) {
+				m.addColumns(editFile.headers)
 			}
+			m.recordProvenance(editFile, changes, operation.Name(), version)
 		}
 	}
 
-	// Detect errors such as duplicate IDs for insert and update.
-	// Logically, it's okay to have duplicate insert and update ids,
-	// If it is duplicated, it is an error because it may be unintended input data.
-	// [ex] when updating twice for the same id.
-	if !array.IsUnique(editIdsAll) {
-		return errors.New("ID is not unique : " + directoryPath + " " + m.name)
+	// The windows are checked on the whole table after every file is applied, since a window may be edited by several files.
+	if len(validationErrors) == 0 {
+		validationErrors = m.checkWindows()
+	}
+	if len(validationErrors) > 0 {
+		return validationErrors
 	}
 
 	return nil
 }
 
+// checkWindows Check the windows of the rows in the location of the TimeWindow,
+// returning only the first problem in ErrorModeFailFast.
+func (m *MasterData) checkWindows() ValidationErrors {
+	_, validationErrors := m.windows(nil)
+	if m.errorMode == ErrorModeFailFast && len(validationErrors) > 0 {
+		return validationErrors[:1]
+	}
+
+	return validationErrors
+}
+
+// editFilePaths Get the paths of the files of the table in the directory of the load type. (ex. insert)
+func (m *MasterData) editFilePaths(directoryPath string, loadType string) ([]string, error) {
+	pathSeparator := string(os.PathSeparator)
+	loadTypePath := directoryPath + pathSeparator + loadType + pathSeparator
+	if !directory.Exist(loadTypePath) {
+		return nil, nil
+	}
+
+	filePaths, err := directory.GetFilePathRecursive(loadTypePath, []string{m.extension})
+	if err != nil {
+		return nil, err
+	}
+
+	var r []string
+	for _, filePath := range filePaths {
+		if matchTableName(m.name, file.BaseFileName(filePath), m.isPartialMatch) {
+			r = append(r, filePath)
+		}
+	}
+
+	return r, nil
+}
+
+// matchTableName Check if the file of the base file name belongs to the table.
+// With partial match, the files whose name starts with the table name belong to it. (ex. items_2 belongs to items)
+func matchTableName(tableName string, baseFileName string, isPartialMatch bool) bool {
+	return tableName == baseFileName || (isPartialMatch && strings.HasPrefix(baseFileName, tableName))
+}
+
 func directoryExists(directoryPath string, loadTypes []string) bool {
 	pathSeparator := string(os.PathSeparator)
 	for _, loadType := range loadTypes {
@@ -135,57 +233,24 @@ func (m *MasterData) GetFilePathRecursive(path string) ([]string, error) {
 	return paths, err
 }
 
-// delete the specified key from the map.
-func (m *MasterData) delete(editMap map[Key]string, filePath string) error {
-	baseIds := PluckId(m.Rows)
-
-	for key := range editMap {
-		if key.Key == "id" {
-			if !array.Contains(baseIds, key.Id) {
-				return errors.New("Attempted to delete a non-existent ID : id " + strconv.Itoa(key.Id) + " " + filePath)
-			}
-		}
-		delete(m.Rows, Key{Id: key.Id, Key: key.Key})
-	}
-
-	return nil
-}
-
-// insert the specified key into the map.
-func (m *MasterData) insert(editMap map[Key]string, filePath string) error {
-	baseIds := PluckId(m.Rows)
-	editIds := PluckId(editMap)
-
-	for _, id := range editIds {
-		if array.Contains(baseIds, id) {
-			return errors.New("Tried to do an insert on an existing ID : id " + strconv.Itoa(id) + " " + filePath)
+// hasValueOf Check if a value of the file is in the table, which is false after a delete.
+func (m *MasterData) hasValueOf(editFile *EditFile) bool {
+	for key := range editFile.rows {
+		if _, ok := m.Rows[key]; ok {
+			return true
 		}
 	}
 
-	for mapKey, value := range editMap {
-		m.Rows[Key{Id: mapKey.Id, Key: mapKey.Key}] = value
-	}
-
-	return nil
+	return false
 }
 
-// update the specified key in the map.
-func (m *MasterData) update(editMap map[Key]string, filePath string) error {
-	baseIds := PluckId(m.Rows)
-	editIds := PluckId(editMap)
-	for _, id := range editIds {
-		if !array.Contains(baseIds, id) {
-			return errors.New("Tried to update a non-existent ID : id " + strconv.Itoa(id) + " " + filePath)
-		}
-	}
-
-	if err := m.delete(editMap, filePath); err != nil {
-		return err
+// hasColumn Check if the table has the column of the key.
+// The columns of the loaded files are used, and the row of the key is checked when they are unknown.
+func (m *MasterData) hasColumn(key Key) bool {
+	if array.Contains(m.columns, key.Key) {
+		return true
 	}
+	_, ok := m.Rows[key]
 
-	if err := m.insert(editMap, filePath); err != nil {
-		return err
-	}
-
-	return nil
+	return ok
 }
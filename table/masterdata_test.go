@@ -376,3 +376,143 @@ func TestLoadByDirectoryPath(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadByDirectoryPath_UpdateMode(t *testing.T) {
+	tests := []struct {
+		name          string
+		updateMode    UpdateMode
+		directoryPath string
+		want          map[Key]string
+		wantErr       bool
+	}{
+		{
+			name:          "UpdateMode1",
+			updateMode:    UpdateModePartial,
+			directoryPath: "./testdata/partial",
+			want: map[Key]string{
+				{Id: 1, Key: "id"}:     "1",
+				{Id: 1, Key: "sample"}: "aaa",
+				{Id: 1, Key: "level"}:  "99",
+			},
+			wantErr: false,
+		},
+		{
+			name:          "UpdateMode2",
+			updateMode:    UpdateModePartial,
+			directoryPath: "./testdata/partial_error",
+			want:          nil,
+			wantErr:       true,
+		},
+		{
+			name:          "UpdateMode3",
+			updateMode:    UpdateModeOverwrite,
+			directoryPath: "./testdata/partial_error",
+			want: map[Key]string{
+				{Id: 1, Key: "id"}:     "1",
+				{Id: 1, Key: "sample"}: "aaa",
+				{Id: 1, Key: "level"}:  "5",
+				{Id: 1, Key: "rank"}:   "3",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewTabular("samples", "csv", map[Key]string{
+				{Id: 1, Key: "id"}:     "1",
+				{Id: 1, Key: "sample"}: "aaa",
+				{Id: 1, Key: "level"}:  "5",
+			}, false)
+			m.SetUpdateMode(tt.updateMode)
+			err := m.LoadByDirectoryPath(tt.directoryPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadByDirectoryPath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(m.Rows, tt.want) {
+				t.Errorf("LoadByDirectoryPath() got = %v, want %v", m.Rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadByDirectoryPath_UpsertReplace(t *testing.T) {
+	tests := []struct {
+		name           string
+		isPartialMatch bool
+		updateMode     UpdateMode
+		directoryPath  string
+		want           map[Key]string
+		wantErr        bool
+	}{
+		{
+			name:          "UpsertReplace1",
+			updateMode:    UpdateModePartial,
+			directoryPath: "./testdata/upsert",
+			want: map[Key]string{
+				{Id: 1, Key: "id"}:     "1",
+				{Id: 1, Key: "sample"}: "aaa",
+				{Id: 1, Key: "level"}:  "99",
+				{Id: 2, Key: "id"}:     "2",
+				{Id: 2, Key: "level"}:  "7",
+			},
+			wantErr: false,
+		},
+		{
+			name:          "UpsertReplace2",
+			updateMode:    UpdateModePartial,
+			directoryPath: "./testdata/upsert_error",
+			want:          nil,
+			wantErr:       true,
+		},
+		{
+			name:           "UpsertReplace3",
+			isPartialMatch: true,
+			directoryPath:  "./testdata/replace",
+			want: map[Key]string{
+				{Id: 2, Key: "id"}:     "2",
+				{Id: 2, Key: "sample"}: "bbb",
+				{Id: 3, Key: "id"}:     "3",
+				{Id: 3, Key: "sample"}: "ccc",
+				{Id: 4, Key: "id"}:     "4",
+				{Id: 4, Key: "sample"}: "ddd",
+			},
+			wantErr: false,
+		},
+		{
+			name:          "UpsertReplace4",
+			directoryPath: "./testdata/replace",
+			want: map[Key]string{
+				{Id: 2, Key: "id"}:     "2",
+				{Id: 2, Key: "sample"}: "bbb",
+				{Id: 4, Key: "id"}:     "4",
+				{Id: 4, Key: "sample"}: "ddd",
+			},
+			wantErr: false,
+		},
+		{
+			name:          "UpsertReplace5",
+			directoryPath: "./testdata/replace_error",
+			want:          nil,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewTabular("samples", "csv", map[Key]string{
+				{Id: 1, Key: "id"}:     "1",
+				{Id: 1, Key: "sample"}: "aaa",
+				{Id: 1, Key: "level"}:  "5",
+			}, tt.isPartialMatch)
+			m.SetUpdateMode(tt.updateMode)
+			err := m.LoadByDirectoryPath(tt.directoryPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadByDirectoryPath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(m.Rows, tt.want) {
+				t.Errorf("LoadByDirectoryPath() got = %v, want %v", m.Rows, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package table
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Operation is an operation directory of a version directory. (ex. insert)
+// The files of the table in the directory are applied to the table by the operation.
+// Before Check, every file is checked against the schema. Other rules, such as the uniqueness of the ids, are up to Check.
+// The provenance of a value is recorded when Apply changes it, with the line of its row in the file.
+type Operation interface {
+	// Name returns the name of the directory of the operation.
+	Name() string
+	// Priority returns the order in which the operation is applied. The operation with the smaller priority is applied first.
+	Priority() int
+	// Check Check that the file can be applied to the table. The problems should be returned as ValidationErrors.
+	Check(m *MasterData, editFile *EditFile, context OperationContext) error
+	// Apply Apply the file to the table, changing it only with SetValue and RemoveValue so that a failed load is undone.
+	Apply(m *MasterData, editFile *EditFile, context OperationContext) error
+}
+
+// OperationContext is the state of the load in which an operation is applied.
+type OperationContext struct {
+	// Version is the name of the version directory.
+	Version string
+	// FileIndex is the number of the file among the files of the operation in the directory, starting at 0.
+	FileIndex int
+	// editedIds is the ids of the files checked by CheckUnique in the version directory.
+	editedIds map[RowId]bool
+}
+
+// CheckUnique Check that the ids of the file are not edited by another file of the version directory that was also
+// checked by CheckUnique. The built-in operations call it in Check.
+// Logically, it's okay to have duplicate insert and update ids,
+// If it is duplicated, it is an error because it may be unintended input data.
+// [ex] when updating twice for the same id.
+func (c OperationContext) CheckUnique(editFile *EditFile) error {
+	var validationErrors ValidationErrors
+	for _, id := range editFile.RowIds() {
+		if c.editedIds[id] {
+			validationErrors = append(validationErrors, editFile.NewError(KindNotUniqueId, id, "ID is not unique"))
+		}
+		c.editedIds[id] = true
+	}
+
+	return validationErrors.orNil()
+}
+
+// The priorities of the built-in operations. A custom operation can be placed between them.
+const (
+	PriorityReplace = 100
+	PriorityDelete  = 200
+	PriorityUpdate  = 300
+	PriorityUpsert  = 400
+	PriorityInsert  = 500
+)
+
+// OperationRegistry is the set of the operations that a load applies.
+type OperationRegistry struct {
+	mutex      sync.RWMutex
+	operations map[string]Operation
+}
+
+// DefaultOperationRegistry is the registry used by the tables that have no registry set.
+var DefaultOperationRegistry = NewOperationRegistry()
+
+// NewOperationRegistry Create a registry with the built-in operations.
+// Avoid immediately UPDATING an INSET record within the same version (since it is an unintended update),
+// so the built-in operations are applied in the order replace, delete, update, upsert, insert.
+//
+//goland:noinspection GoUnusedExportedFunction
+func NewOperationRegistry() *OperationRegistry {
+	r := &OperationRegistry{operations: make(map[string]Operation)}
+	for _, operation := range []Operation{replaceOperation{}, deleteOperation{}, updateOperation{}, upsertOperation{}, insertOperation{}} {
+		r.operations[operation.Name()] = operation
+	}
+
+	return r
+}
+
+// RegisterOperation Register the operation to DefaultOperationRegistry.
+//
+//goland:noinspection GoUnusedExportedFunction
+func RegisterOperation(operation Operation) error {
+	return DefaultOperationRegistry.Register(operation)
+}
+
+// Register Register the operation. The name must not be registered yet.
+func (r *OperationRegistry) Register(operation Operation) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if operation.Name() == "" {
+		return errors.New("Operation name is empty")
+	}
+	if _, ok := r.operations[operation.Name()]; ok {
+		return errors.New("Operation is already registered : " + operation.Name())
+	}
+	r.operations[operation.Name()] = operation
+
+	return nil
+}
+
+// Operations Get the operations in the order they are applied. The same priority is ordered by the name.
+func (r *OperationRegistry) Operations() []Operation {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	operations := make([]Operation, 0, len(r.operations))
+	for _, operation := range r.operations {
+		operations = append(operations, operation)
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		if operations[i].Priority() != operations[j].Priority() {
+			return operations[i].Priority() < operations[j].Priority()
+		}
+		return operations[i].Name() < operations[j].Name()
+	})
+
+	return operations
+}
+
+// Names Get the names of the operations in the order they are applied.
+func (r *OperationRegistry) Names() []string {
+	return operationNames(r.Operations())
+}
+
+// SetOperationRegistry Set the operations that the load applies. If nil, DefaultOperationRegistry is used.
+func (m *MasterData) SetOperationRegistry(operations *OperationRegistry) {
+	m.operations = operations
+}
+
+// operationRegistry Get the operations that the load applies.
+func (m *MasterData) operationRegistry() *OperationRegistry {
+	if m.operations == nil {
+		return DefaultOperationRegistry
+	}
+
+	return m.operations
+}
+
+// operationNames Get the names of the operations.
+func operationNames(operations []Operation) []string {
+	names := make([]string, 0, len(operations))
+	for _, operation := range operations {
+		names = append(names, operation.Name())
+	}
+
+	return names
+}
+
+// insertOperation adds new rows.
+type insertOperation struct{}
+
+func (insertOperation) Name() string {
+	return "insert"
+}
+
+func (insertOperation) Priority() int {
+	return PriorityInsert
+}
+
+func (insertOperation) Check(m *MasterData, editFile *EditFile, context OperationContext) error {
+	if err := context.CheckUnique(editFile); err != nil {
+		return err
+	}
+
+	var validationErrors ValidationErrors
+	for _, id := range editFile.RowIds() {
+		if m.HasRow(id) {
+			validationErrors = append(validationErrors, editFile.NewError(KindInsertExistingId, id, "Tried to do an insert on an existing ID"))
+		}
+	}
+
+	return validationErrors.orNil()
+}
+
+func (insertOperation) Apply(m *MasterData, editFile *EditFile, _ OperationContext) error {
+	setValues(m, editFile)
+	return nil
+}
+
+// updateOperation overwrites the columns of the existing rows.
+type updateOperation struct{}
+
+func (updateOperation) Name() string {
+	return "update"
+}
+
+func (updateOperation) Priority() int {
+	return PriorityUpdate
+}
+
+func (updateOperation) Check(m *MasterData, editFile *EditFile, context OperationContext) error {
+	if err := context.CheckUnique(editFile); err != nil {
+		return err
+	}
+
+	var validationErrors ValidationErrors
+	for _, id := range editFile.RowIds() {
+		if !m.HasRow(id) {
+			validationErrors = append(validationErrors, editFile.NewError(KindUpdateMissingId, id, "Tried to update a non-existent ID"))
+			continue
+		}
+		validationErrors = append(validationErrors, m.checkColumns(editFile, id, KindUpdateMissingColumn, "Tried to update a non-existent column")...)
+	}
+
+	return validationErrors.orNil()
+}
+
+func (updateOperation) Apply(m *MasterData, editFile *EditFile, _ OperationContext) error {
+	setValues(m, editFile)
+	return nil
+}
+
+// deleteOperation removes the values of the existing rows in the file.
+type deleteOperation struct{}
+
+func (deleteOperation) Name() string {
+	return "delete"
+}
+
+func (deleteOperation) Priority() int {
+	return PriorityDelete
+}
+
+func (deleteOperation) Check(m *MasterData, editFile *EditFile, context OperationContext) error {
+	if err := context.CheckUnique(editFile); err != nil {
+		return err
+	}
+
+	var validationErrors ValidationErrors
+	for _, id := range editFile.RowIds() {
+		if !m.HasRow(id) {
+			validationErrors = append(validationErrors, editFile.NewError(KindDeleteMissingId, id, "Attempted to delete a non-existent ID"))
+		}
+	}
+
+	return validationErrors.orNil()
+}
+
+func (deleteOperation) Apply(m *MasterData, editFile *EditFile, _ OperationContext) error {
+	for key := range editFile.rows {
+		m.RemoveValue(key)
+	}
+
+	return nil
+}
+
+// upsertOperation updates the rows that exist and inserts the others.
+type upsertOperation struct{}
+
+func (upsertOperation) Name() string {
+	return "upsert"
+}
+
+func (upsertOperation) Priority() int {
+	return PriorityUpsert
+}
+
+func (upsertOperation) Check(m *MasterData, editFile *EditFile, context OperationContext) error {
+	if err := context.CheckUnique(editFile); err != nil {
+		return err
+	}
+
+	var validationErrors ValidationErrors
+	for _, id := range editFile.RowIds() {
+		if m.HasRow(id) {
+			validationErrors = append(validationErrors, m.checkColumns(editFile, id, KindUpsertMissingColumn, "Tried to upsert a non-existent column")...)
+		}
+	}
+
+	return validationErrors.orNil()
+}
+
+func (upsertOperation) Apply(m *MasterData, editFile *EditFile, _ OperationContext) error {
+	setValues(m, editFile)
+	return nil
+}
+
+// replaceOperation swaps in the whole table.
+// The first replace file of a directory removes the current rows and columns, and the others add their rows,
+// so that the files of a partial match make up the table together.
+type replaceOperation struct{}
+
+func (replaceOperation) Name() string {
+	return "replace"
+}
+
+func (replaceOperation) Priority() int {
+	return PriorityReplace
+}
+
+func (replaceOperation) Check(_ *MasterData, editFile *EditFile, context OperationContext) error {
+	// The ids of the replace files are unique in the directory, so they do not conflict with each other.
+	return context.CheckUnique(editFile)
+}
+
+func (replaceOperation) Apply(m *MasterData, editFile *EditFile, context OperationContext) error {
+	if context.FileIndex == 0 {
+		for key := range m.Rows {
+			m.RemoveValue(key)
+		}
+		// The columns are restored by the journal when the load fails.
+		m.columns = nil
+	}
+	setValues(m, editFile)
+
+	return nil
+}
+
+// setValues Set the values of the file, and remove the values cleared by the null marker.
+func setValues(m *MasterData, editFile *EditFile) {
+	for key, value := range editFile.rows {
+		m.SetValue(key, value)
+	}
+	for key := range editFile.cleared {
+		m.RemoveValue(key)
+	}
+}
+
+// checkColumns Check that the table has the columns of the row of the file, in UpdateModePartial.
+func (m *MasterData) checkColumns(editFile *EditFile, id RowId, kind ErrorKind, message string) ValidationErrors {
+	if m.updateMode != UpdateModePartial {
+		return nil
+	}
+
+	var validationErrors ValidationErrors
+	for _, column := range editFile.headers {
+		_, ok := editFile.rows[id.Key(column)]
+		if (ok || editFile.cleared[id.Key(column)]) && !m.hasColumn(id.Key(column)) {
+			validationError := editFile.NewError(kind, id, message)
+			validationError.Column = column
+			validationErrors = append(validationErrors, validationError)
+		}
+	}
+
+	return validationErrors
+}
@@ -0,0 +1,130 @@
+package table
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// disableOperation marks the existing rows as disabled instead of deleting them.
+type disableOperation struct{}
+
+func (disableOperation) Name() string {
+	return "disable"
+}
+
+func (disableOperation) Priority() int {
+	return PriorityDelete + 1
+}
+
+func (disableOperation) Check(m *MasterData, editFile *EditFile, _ OperationContext) error {
+	var validationErrors ValidationErrors
+	for _, id := range editFile.RowIds() {
+		if !m.HasRow(id) {
+			validationErrors = append(validationErrors, editFile.NewError("disable_missing_id", id, "Tried to disable a non-existent ID"))
+		}
+	}
+	if len(validationErrors) > 0 {
+		return validationErrors
+	}
+
+	return nil
+}
+
+func (disableOperation) Apply(m *MasterData, editFile *EditFile, _ OperationContext) error {
+	for _, id := range editFile.RowIds() {
+		m.SetValue(id.Key("is_disabled"), "true")
+	}
+
+	return nil
+}
+
+func TestOperationRegistry_Register(t *testing.T) {
+	r := NewOperationRegistry()
+	if err := r.Register(disableOperation{}); err != nil {
+		t.Errorf("Register() error = %v", err)
+	}
+	if err := r.Register(insertOperation{}); err == nil {
+		t.Errorf("Register() error = nil, want error of the registered name")
+	}
+
+	want := []string{"replace", "delete", "disable", "update", "upsert", "insert"}
+	if got := r.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	if got := DefaultOperationRegistry.Names(); len(got) != 5 {
+		t.Errorf("DefaultOperationRegistry.Names() = %v, want the built-in operations", got)
+	}
+}
+
+func TestLoadByDirectoryPath_CustomOperation(t *testing.T) {
+	r := NewOperationRegistry()
+	if err := r.Register(disableOperation{}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewTabular("samples", "csv", testJournalRows(), false)
+	m.SetOperationRegistry(r)
+	if err := m.LoadByDirectoryPath("./testdata/disable"); err != nil {
+		t.Errorf("LoadByDirectoryPath() error = %v", err)
+		return
+	}
+	want := testJournalRows()
+	want[Key{Id: 1, Key: "is_disabled"}] = "true"
+	if !reflect.DeepEqual(m.Rows, want) {
+		t.Errorf("LoadByDirectoryPath() got = %v, want %v", m.Rows, want)
+	}
+
+	err := m.LoadByDirectoryPath("./testdata/disable_error")
+	var validationErrors ValidationErrors
+	if !errors.As(err, &validationErrors) || validationErrors[0].Kind != "disable_missing_id" {
+		t.Errorf("LoadByDirectoryPath() error = %v, want disable_missing_id", err)
+	}
+
+	m = NewTabular("samples", "csv", testJournalRows(), false)
+	if err = m.LoadByDirectoryPath("./testdata/disable"); err == nil {
+		t.Errorf("LoadByDirectoryPath() without the operation error = nil, want error")
+	}
+}
+
+func TestLoadByDirectoryPath_CustomOperationProvenance(t *testing.T) {
+	r := NewOperationRegistry()
+	if err := r.Register(disableOperation{}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewTabular("samples", "csv", testJournalRows(), false)
+	m.SetOperationRegistry(r)
+	m.EnableProvenance()
+	if err := m.LoadByDirectoryPath("./testdata/disable_repeat"); err != nil {
+		t.Errorf("LoadByDirectoryPath() error = %v", err)
+		return
+	}
+
+	want := Provenance{Version: "disable_repeat", Operation: "disable", FilePath: "testdata/disable_repeat/disable/samples.csv", Line: 2}
+	if got, ok := m.Provenance(Key{Id: 1, Key: "is_disabled"}); !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("Provenance() = %v, want %v", got, want)
+	}
+	if got, ok := m.Provenance(Key{Id: 1, Key: "level"}); !ok || got.Operation != "update" {
+		t.Errorf("Provenance() = %v, want the update", got)
+	}
+}
+
+func TestLoadByDirectoryPath_ReplaceColumns(t *testing.T) {
+	m := NewTabular("samples", "csv", testJournalRows(), false)
+	m.SetColumns([]string{"id", "sample", "level"})
+	m.SetUpdateMode(UpdateModePartial)
+	if err := m.LoadByDirectoryPath("./testdata/replace_columns/1_0_0_0"); err != nil {
+		t.Errorf("LoadByDirectoryPath() error = %v", err)
+		return
+	}
+	if got, want := m.Columns(), []string{"id", "sample"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Columns() = %v, want %v", got, want)
+	}
+
+	err := m.LoadByDirectoryPath("./testdata/replace_columns/1_0_1_0")
+	var validationErrors ValidationErrors
+	if !errors.As(err, &validationErrors) || validationErrors[0].Kind != KindUpdateMissingColumn {
+		t.Errorf("LoadByDirectoryPath() error = %v, want %v", err, KindUpdateMissingColumn)
+	}
+}
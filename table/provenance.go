@@ -0,0 +1,90 @@
+package table
+
+import (
+	"strconv"
+
+	"github.com/stepupdream/go-support-tool/delimited"
+)
+
+// Provenance is the record of the file that last wrote a value.
+type Provenance struct {
+	Version   string
+	Operation string
+	FilePath  string
+	Line      int
+}
+
+// EnableProvenance Start recording which version directory and file wrote each value.
+// Only the values loaded after this call are recorded.
+func (m *MasterData) EnableProvenance() {
+	if m.provenance == nil {
+		m.provenance = make(map[Key]Provenance)
+	}
+}
+
+// Provenance Get the record of the file that last wrote the value of the key.
+// If provenance is not enabled or the value was not loaded from a file, return false.
+func (m *MasterData) Provenance(key Key) (Provenance, bool) {
+	provenance, ok := m.provenance[key]
+
+	return provenance, ok
+}
+
+// RowProvenance Get the records of the values of the specified row by column.
+func (m *MasterData) RowProvenance(id RowId) map[string]Provenance {
+	r := make(map[string]Provenance)
+	for _, column := range m.Columns() {
+		if provenance, ok := m.provenance[id.Key(column)]; ok {
+			r[column] = provenance
+		}
+	}
+
+	return r
+}
+
+// ExportProvenance Write the provenance of every value to the specified file, in the same order as Export.
+// The file has the id, column, version, operation, file and line columns.
+func (m *MasterData) ExportProvenance(path string) error {
+	rows := [][]string{{"id", "column", "version", "operation", "file", "line"}}
+	columns := m.Columns()
+
+	for _, id := range m.RowIds() {
+		for _, column := range columns {
+			provenance, ok := m.provenance[id.Key(column)]
+			if !ok {
+				continue
+			}
+			rows = append(rows, []string{
+				id.String(),
+				column,
+				provenance.Version,
+				provenance.Operation,
+				provenance.FilePath,
+				strconv.Itoa(provenance.Line),
+			})
+		}
+	}
+
+	return delimited.CreateNewFile(path, rows)
+}
+
+// recordProvenance Record the provenance of the values that the operation changed by the file.
+// The values that the operation removed, such as by a delete, no longer have provenance.
+func (m *MasterData) recordProvenance(editFile *EditFile, changes map[Key]bool, operation string, version string) {
+	if m.provenance == nil {
+		return
+	}
+
+	for key := range changes {
+		if _, ok := m.Rows[key]; !ok {
+			m.removeProvenance(key)
+			continue
+		}
+		m.setProvenance(key, Provenance{
+			Version:   version,
+			Operation: operation,
+			FilePath:  editFile.path,
+			Line:      editFile.lineNumbers[key.RowId()],
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package table
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stepupdream/go-support-tool/delimited"
+)
+
+func TestProvenance(t *testing.T) {
+	m := NewTabular("samples", "csv", map[Key]string{}, false)
+	m.EnableProvenance()
+	if err := m.LoadByVersionRange("./testdata/versions", "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		key    Key
+		want   Provenance
+		wantOk bool
+	}{
+		{
+			name: "Provenance1",
+			key:  Key{Id: 2, Key: "sample"},
+			want: Provenance{
+				Version:   "1_0_1_0",
+				Operation: "update",
+				FilePath:  filepath.Join("testdata", "versions", "1_0_1_0", "update", "samples.csv"),
+				Line:      2,
+			},
+			wantOk: true,
+		},
+		{
+			name: "Provenance2",
+			key:  Key{Id: 100, Key: "level"},
+			want: Provenance{
+				Version:   "1_0_10_0",
+				Operation: "insert",
+				FilePath:  filepath.Join("testdata", "versions", "1_0_10_0", "insert", "samples.csv"),
+				Line:      2,
+			},
+			wantOk: true,
+		},
+		{
+			name:   "Provenance3",
+			key:    Key{Id: 1, Key: "sample"},
+			want:   Provenance{},
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := m.Provenance(tt.key)
+			if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Provenance() got = %v %v, want %v %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+
+	if got := m.RowProvenance(RowId{Id: 2}); len(got) != 3 || got["level"].Operation != "update" {
+		t.Errorf("RowProvenance() got = %v", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "samples_provenance.csv")
+	if err := m.ExportProvenance(path); err != nil {
+		t.Errorf("ExportProvenance() error = %v", err)
+		return
+	}
+	rows, err := delimited.Load(path, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 7 || !reflect.DeepEqual(rows[1], []string{"2", "id", "1_0_1_0", "update", filepath.Join("testdata", "versions", "1_0_1_0", "update", "samples.csv"), "2"}) {
+		t.Errorf("ExportProvenance() rows = %v", rows)
+	}
+}
@@ -0,0 +1,410 @@
+package table
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Operator is the comparison of a where clause.
+type Operator string
+
+const (
+	OpEqual          Operator = "="
+	OpNotEqual       Operator = "!="
+	OpLess           Operator = "<"
+	OpLessOrEqual    Operator = "<="
+	OpGreater        Operator = ">"
+	OpGreaterOrEqual Operator = ">="
+)
+
+// AggregateFunction is the function of an aggregate column of a grouped query.
+type AggregateFunction string
+
+const (
+	AggregateCount AggregateFunction = "count"
+	AggregateSum   AggregateFunction = "sum"
+	AggregateMin   AggregateFunction = "min"
+	AggregateMax   AggregateFunction = "max"
+)
+
+type condition struct {
+	column   string
+	operator Operator
+	value    string
+}
+
+type order struct {
+	column     string
+	descending bool
+}
+
+type aggregate struct {
+	function AggregateFunction
+	column   string
+}
+
+// name returns the column name of the aggregate in the result. (ex. count, sum_price)
+func (a aggregate) name() string {
+	if a.function == AggregateCount {
+		return string(AggregateCount)
+	}
+
+	return string(a.function) + "_" + a.column
+}
+
+// Query is a query on a snapshot of MasterData.
+// Values are compared by the type of the column in the schema. A column that is not in the schema is compared
+// as numbers when both values are numbers, and as text otherwise.
+// A row that does not have the value of a column does not match any where clause on the column.
+// An empty value of an int, float, bool or date column is treated the same as a missing value.
+type Query struct {
+	table        *MasterData
+	conditions   []condition
+	orders       []order
+	columns      []string
+	groupColumns []string
+	aggregates   []aggregate
+	limit        int
+}
+
+// Query Create a new query on the table.
+func (m *MasterData) Query() *Query {
+	return &Query{table: m}
+}
+
+// Where Add a condition that the value of the column is compared with the value by the operator.
+// All conditions must match.
+func (q *Query) Where(column string, operator Operator, value string) *Query {
+	q.conditions = append(q.conditions, condition{column: column, operator: operator, value: value})
+	return q
+}
+
+// OrderBy Add a column to sort the result by. The columns are applied in the order they are added.
+// The rows are in the order of the row ids when no column is added.
+func (q *Query) OrderBy(column string, descending bool) *Query {
+	q.orders = append(q.orders, order{column: column, descending: descending})
+	return q
+}
+
+// Select Set the columns of the result. If not set, every column of the table or the grouped result is returned.
+func (q *Query) Select(columns ...string) *Query {
+	q.columns = columns
+	return q
+}
+
+// GroupBy Group the rows by the values of the columns. The result has the group columns and the aggregate columns.
+func (q *Query) GroupBy(columns ...string) *Query {
+	q.groupColumns = columns
+	return q
+}
+
+// Count Add the number of rows of each group as the column count.
+func (q *Query) Count() *Query {
+	q.aggregates = append(q.aggregates, aggregate{function: AggregateCount})
+	return q
+}
+
+// Sum Add the sum of the column of each group as the column sum_<column>.
+func (q *Query) Sum(column string) *Query {
+	q.aggregates = append(q.aggregates, aggregate{function: AggregateSum, column: column})
+	return q
+}
+
+// Min Add the minimum value of the column of each group as the column min_<column>.
+func (q *Query) Min(column string) *Query {
+	q.aggregates = append(q.aggregates, aggregate{function: AggregateMin, column: column})
+	return q
+}
+
+// Max Add the maximum value of the column of each group as the column max_<column>.
+func (q *Query) Max(column string) *Query {
+	q.aggregates = append(q.aggregates, aggregate{function: AggregateMax, column: column})
+	return q
+}
+
+// Limit Set the maximum number of rows of the result. If 0, all rows are returned.
+func (q *Query) Limit(limit int) *Query {
+	q.limit = limit
+	return q
+}
+
+// Rows Run the query and get the result. The first row is the header, and a missing value is an empty string.
+func (q *Query) Rows() ([][]string, error) {
+	var results []map[string]string
+	for _, id := range q.table.RowIds() {
+		row := q.table.Row(id)
+		matched, err := q.match(row)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			results = append(results, row)
+		}
+	}
+
+	columns := q.columns
+	if len(q.groupColumns) > 0 || len(q.aggregates) > 0 {
+		var err error
+		if results, err = q.group(results); err != nil {
+			return nil, err
+		}
+		if len(columns) == 0 {
+			columns = append([]string{}, q.groupColumns...)
+			for _, a := range q.aggregates {
+				columns = append(columns, a.name())
+			}
+		}
+	} else if len(columns) == 0 {
+		columns = q.table.Columns()
+	}
+
+	if err := q.sort(results); err != nil {
+		return nil, err
+	}
+	if q.limit > 0 && len(results) > q.limit {
+		results = results[:q.limit]
+	}
+
+	r := [][]string{columns}
+	for _, result := range results {
+		values := make([]string, 0, len(columns))
+		for _, column := range columns {
+			values = append(values, result[column])
+		}
+		r = append(r, values)
+	}
+
+	return r, nil
+}
+
+// match Check if the row matches all conditions.
+func (q *Query) match(row map[string]string) (bool, error) {
+	for _, c := range q.conditions {
+		value, ok := q.value(row, c.column)
+		if !ok {
+			return false, nil
+		}
+		compared, err := q.compare(c.column, value, c.value)
+		if err != nil {
+			return false, err
+		}
+
+		var matched bool
+		switch c.operator {
+		case OpEqual:
+			matched = compared == 0
+		case OpNotEqual:
+			matched = compared != 0
+		case OpLess:
+			matched = compared < 0
+		case OpLessOrEqual:
+			matched = compared <= 0
+		case OpGreater:
+			matched = compared > 0
+		case OpGreaterOrEqual:
+			matched = compared >= 0
+		default:
+			return false, errors.New("Unknown operator : " + string(c.operator))
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// group Group the rows by the group columns and calculate the aggregates. The groups are in the order they first appear.
+func (q *Query) group(rows []map[string]string) ([]map[string]string, error) {
+	var keys []string
+	groups := make(map[string][]map[string]string)
+	for _, row := range rows {
+		values := make([]string, 0, len(q.groupColumns))
+		for _, column := range q.groupColumns {
+			values = append(values, row[column])
+		}
+		groupKey := strings.Join(values, "\x00")
+		if _, ok := groups[groupKey]; !ok {
+			keys = append(keys, groupKey)
+		}
+		groups[groupKey] = append(groups[groupKey], row)
+	}
+
+	r := make([]map[string]string, 0, len(keys))
+	for _, groupKey := range keys {
+		groupRows := groups[groupKey]
+		result := make(map[string]string)
+		for _, column := range q.groupColumns {
+			if value, ok := groupRows[0][column]; ok {
+				result[column] = value
+			}
+		}
+		for _, a := range q.aggregates {
+			value, err := q.aggregate(a, groupRows)
+			if err != nil {
+				return nil, err
+			}
+			result[a.name()] = value
+		}
+		r = append(r, result)
+	}
+
+	return r, nil
+}
+
+// aggregate Calculate the aggregate of the rows. The rows without the value of the column are ignored.
+func (q *Query) aggregate(a aggregate, rows []map[string]string) (string, error) {
+	if a.function == AggregateCount {
+		return strconv.Itoa(len(rows)), nil
+	}
+
+	var r string
+	var sum float64
+	found := false
+	for _, row := range rows {
+		value, ok := row[a.column]
+		if !ok || value == "" {
+			continue
+		}
+
+		switch a.function {
+		case AggregateSum:
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return "", errors.New("Value is not number : " + a.column + " : " + value)
+			}
+			sum += number
+		case AggregateMin, AggregateMax:
+			if found {
+				compared, err := q.compare(a.column, value, r)
+				if err != nil {
+					return "", err
+				}
+				if (a.function == AggregateMin && compared >= 0) || (a.function == AggregateMax && compared <= 0) {
+					continue
+				}
+			}
+			r = value
+		}
+		found = true
+	}
+
+	if a.function == AggregateSum {
+		return strconv.FormatFloat(sum, 'f', -1, 64), nil
+	}
+
+	return r, nil
+}
+
+// sort Sort the rows by the order columns. A missing value comes first, also in descending order.
+func (q *Query) sort(rows []map[string]string) error {
+	var err error
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, o := range q.orders {
+			value1, ok1 := q.value(rows[i], o.column)
+			value2, ok2 := q.value(rows[j], o.column)
+			compared := 0
+			switch {
+			case !ok1 && !ok2:
+			case !ok1:
+				return true
+			case !ok2:
+				return false
+			default:
+				var compareErr error
+				if compared, compareErr = q.compare(o.column, value1, value2); compareErr != nil && err == nil {
+					err = compareErr
+				}
+			}
+			if compared == 0 {
+				continue
+			}
+			if o.descending {
+				return compared > 0
+			}
+			return compared < 0
+		}
+		return false
+	})
+
+	return err
+}
+
+// value Get the value of the column of the row.
+// An empty value of a column that is declared with a type other than string is not a value, so false is returned.
+func (q *Query) value(row map[string]string, column string) (string, bool) {
+	value, ok := row[column]
+	if !ok || value != "" || q.table.schema == nil {
+		return value, ok
+	}
+	if c, declared := q.table.schema.Column(column); declared && c.Type != TypeString {
+		return "", false
+	}
+
+	return value, true
+}
+
+// compare Compare the values by the type of the column. It returns -1, 0 or 1.
+func (q *Query) compare(column string, value1 string, value2 string) (int, error) {
+	columnType, declared := TypeString, false
+	if q.table.schema != nil {
+		var c Column
+		if c, declared = q.table.schema.Column(column); declared {
+			columnType = c.Type
+		}
+	}
+
+	switch {
+	case columnType == TypeInt || columnType == TypeFloat || !declared:
+		number1, err1 := strconv.ParseFloat(value1, 64)
+		number2, err2 := strconv.ParseFloat(value2, 64)
+		if err1 == nil && err2 == nil {
+			return compareOrdered(number1, number2), nil
+		}
+		if declared {
+			return 0, errors.New("Value is not " + columnType.String() + " : " + column)
+		}
+	case columnType == TypeBool:
+		bool1, err1 := strconv.ParseBool(value1)
+		bool2, err2 := strconv.ParseBool(value2)
+		if err1 != nil || err2 != nil {
+			return 0, errors.New("Value is not bool : " + column)
+		}
+		return compareOrdered(boolNumber(bool1), boolNumber(bool2)), nil
+	case columnType == TypeDate:
+		c, _ := q.table.schema.Column(column)
+		time1, err1 := time.Parse(c.layout(), value1)
+		time2, err2 := time.Parse(c.layout(), value2)
+		if err1 != nil || err2 != nil {
+			return 0, errors.New("Value is not date : " + column)
+		}
+		return time1.Compare(time2), nil
+	}
+
+	return strings.Compare(value1, value2), nil
+}
+
+// compareOrdered Compare the numbers. It returns -1, 0 or 1.
+func compareOrdered(number1 float64, number2 float64) int {
+	switch {
+	case number1 < number2:
+		return -1
+	case number1 > number2:
+		return 1
+	}
+
+	return 0
+}
+
+// boolNumber returns 1 for true and 0 for false.
+func boolNumber(value bool) float64 {
+	if value {
+		return 1
+	}
+
+	return 0
+}
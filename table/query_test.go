@@ -0,0 +1,112 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testQueryTable() *MasterData {
+	m := NewTabular("items", "csv", map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "rarity"}: "R", {Id: 1, Key: "price"}: "100",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "rarity"}: "N", {Id: 2, Key: "price"}: "20",
+		{Id: 3, Key: "id"}: "3", {Id: 3, Key: "rarity"}: "R", {Id: 3, Key: "price"}: "300",
+		{Id: 10, Key: "id"}: "10", {Id: 10, Key: "rarity"}: "N",
+	}, false)
+	m.SetColumns([]string{"id", "rarity", "price"})
+
+	return m
+}
+
+func TestQuery_Rows(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   *Query
+		want    [][]string
+		wantErr bool
+	}{
+		{
+			name:  "Rows1",
+			query: testQueryTable().Query().Where("price", OpGreaterOrEqual, "100"),
+			want:  [][]string{{"id", "rarity", "price"}, {"1", "R", "100"}, {"3", "R", "300"}},
+		},
+		{
+			name:  "Rows2",
+			query: testQueryTable().Query().OrderBy("rarity", false).OrderBy("id", true).Select("id", "rarity").Limit(3),
+			want:  [][]string{{"id", "rarity"}, {"10", "N"}, {"2", "N"}, {"3", "R"}},
+		},
+		{
+			name:  "Rows3",
+			query: testQueryTable().Query().GroupBy("rarity").Count().Sum("price").Min("price").Max("id").OrderBy("rarity", false),
+			want: [][]string{
+				{"rarity", "count", "sum_price", "min_price", "max_id"},
+				{"N", "2", "20", "20", "10"},
+				{"R", "2", "400", "100", "3"},
+			},
+		},
+		{
+			name:    "Rows4",
+			query:   testQueryTable().Query().Where("price", "~", "1"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query.Rows()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Rows() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rows() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_Rows_Schema(t *testing.T) {
+	m := testQueryTable()
+	m.SetSchema(&Schema{Columns: []Column{{Name: "price", Type: TypeString}}})
+
+	got, err := m.Query().Where("price", OpLess, "3").Select("id").Rows()
+	if err != nil {
+		t.Errorf("Rows() error = %v", err)
+		return
+	}
+	want := [][]string{{"id"}, {"1"}, {"2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Rows() got = %v, want %v", got, want)
+	}
+}
+
+func TestQuery_Rows_NullableEmpty(t *testing.T) {
+	m := NewTabular("items", "csv", map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "rate"}: "1.5",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "rate"}: "",
+		{Id: 3, Key: "id"}: "3", {Id: 3, Key: "rate"}: "0.5",
+		{Id: 4, Key: "id"}: "4",
+	}, false)
+	m.SetColumns([]string{"id", "rate"})
+	m.SetSchema(&Schema{Columns: []Column{{Name: "rate", Type: TypeFloat, Nullable: true}}})
+
+	tests := []struct {
+		name  string
+		query *Query
+		want  [][]string
+	}{
+		{name: "Where", query: m.Query().Where("rate", OpNotEqual, "1.5").Select("id"), want: [][]string{{"id"}, {"3"}}},
+		{name: "OrderBy", query: m.Query().OrderBy("rate", false).Select("id"), want: [][]string{{"id"}, {"2"}, {"4"}, {"3"}, {"1"}}},
+		{name: "OrderByDescending", query: m.Query().OrderBy("rate", true).Select("id"), want: [][]string{{"id"}, {"2"}, {"4"}, {"1"}, {"3"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query.Rows()
+			if err != nil {
+				t.Errorf("Rows() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rows() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package table
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// DanglingReference is a value that refers to a row that does not exist in the referenced table.
+type DanglingReference struct {
+	Table           string
+	Id              RowId
+	Column          string
+	Value           string
+	ReferencedTable string
+}
+
+// Error returns the message with the position of the reference.
+func (d DanglingReference) Error() string {
+	return "Referenced ID does not exist : " + d.Table + " id " + d.Id.String() + " column " + d.Column + " -> " + d.ReferencedTable + " id " + d.Value
+}
+
+// CheckReferences List every value that refers to a non-existent row.
+// The references are declared by Column.Reference in the schema of each table, and the key of the map is the table name.
+// An empty value is not checked. The result is ordered by table name, id and column.
+//
+//goland:noinspection GoUnusedExportedFunction
+func CheckReferences(tables map[string]*MasterData) ([]DanglingReference, error) {
+	var tableNames []string
+	for tableName := range tables {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+
+	var r []DanglingReference
+	for _, tableName := range tableNames {
+		m := tables[tableName]
+		if m.schema == nil {
+			continue
+		}
+
+		for _, column := range m.schema.Columns {
+			if column.Reference == "" {
+				continue
+			}
+
+			referencedTable, ok := tables[column.Reference]
+			if !ok {
+				return nil, errors.New("Not found referenced table : " + column.Reference + " " + tableName + "." + column.Name)
+			}
+			if len(referencedTable.schema.keyColumns()) != 1 {
+				return nil, errors.New("A table with composite key columns cannot be referenced : " + column.Reference + " " + tableName + "." + column.Name)
+			}
+
+			for _, id := range m.RowIds() {
+				value := m.Row(id)[column.Name]
+				if value == "" {
+					continue
+				}
+				if !referencedTable.HasRow(referencedRowId(referencedTable, value)) {
+					r = append(r, DanglingReference{
+						Table:           tableName,
+						Id:              id,
+						Column:          column.Name,
+						Value:           value,
+						ReferencedTable: column.Reference,
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(r, func(i, j int) bool {
+		if r[i].Table != r[j].Table {
+			return r[i].Table < r[j].Table
+		}
+		if r[i].Id != r[j].Id {
+			return r[i].Id.Less(r[j].Id)
+		}
+		return r[i].Column < r[j].Column
+	})
+
+	return r, nil
+}
+
+// referencedRowId Convert the value into the row id of the referenced table.
+// If the referenced table is keyed by the numeric id and the value is not numeric, an id that never exists is returned.
+func referencedRowId(referencedTable *MasterData, value string) RowId {
+	if !referencedTable.schema.isNumericKey() {
+		return codeRowId(value)
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return codeRowId(value)
+	}
+
+	return RowId{Id: id}
+}
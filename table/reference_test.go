@@ -0,0 +1,87 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckReferences(t *testing.T) {
+	items := NewTabular("items", "csv", map[Key]string{
+		{Id: 1, Key: "id"}: "1",
+		{Id: 2, Key: "id"}: "2",
+	}, false)
+	weapons := NewTabular("weapons", "csv", map[Key]string{
+		{Code: "sword", Key: "code"}: "sword",
+	}, false)
+	weapons.SetSchema(&Schema{KeyColumns: []string{"code"}})
+	rewards := NewTabular("rewards", "csv", map[Key]string{
+		{Id: 1, Key: "id"}:      "1",
+		{Id: 1, Key: "item_id"}: "1",
+		{Id: 1, Key: "weapon"}:  "sword",
+		{Id: 2, Key: "id"}:      "2",
+		{Id: 2, Key: "item_id"}: "3",
+		{Id: 2, Key: "weapon"}:  "",
+		{Id: 3, Key: "id"}:      "3",
+		{Id: 3, Key: "item_id"}: "x",
+		{Id: 3, Key: "weapon"}:  "bow",
+	}, false)
+	rewards.SetSchema(&Schema{
+		Columns: []Column{
+			{Name: "item_id", Type: TypeString, Reference: "items"},
+			{Name: "weapon", Type: TypeString, Nullable: true, Reference: "weapons"},
+		},
+	})
+
+	tests := []struct {
+		name    string
+		tables  map[string]*MasterData
+		want    []DanglingReference
+		wantErr bool
+	}{
+		{
+			name:   "CheckReferences1",
+			tables: map[string]*MasterData{"items": items, "weapons": weapons, "rewards": rewards},
+			want: []DanglingReference{
+				{Table: "rewards", Id: RowId{Id: 2}, Column: "item_id", Value: "3", ReferencedTable: "items"},
+				{Table: "rewards", Id: RowId{Id: 3}, Column: "item_id", Value: "x", ReferencedTable: "items"},
+				{Table: "rewards", Id: RowId{Id: 3}, Column: "weapon", Value: "bow", ReferencedTable: "weapons"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "CheckReferences2",
+			tables:  map[string]*MasterData{"items": items, "rewards": rewards},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckReferences(tt.tables)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckReferences() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckReferences() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckReferences_DeletedRow(t *testing.T) {
+	items := NewTabular("items", "csv", map[Key]string{{Id: 1, Key: "id"}: "1"}, false)
+	drops := NewTabular("drops", "csv", map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "item_id"}: "1",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "item_id"}: "9",
+	}, false)
+	drops.SetSchema(&Schema{Columns: []Column{{Name: "item_id", Type: TypeInt, Reference: "items"}}})
+	if err := drops.LoadByDirectoryPath("./testdata/reference_delete"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := CheckReferences(map[string]*MasterData{"items": items, "drops": drops})
+	if err != nil || len(got) != 0 {
+		t.Errorf("CheckReferences() got = %v, error = %v, want none", got, err)
+	}
+}
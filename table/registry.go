@@ -0,0 +1,204 @@
+package table
+
+import (
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/go-support-tool/directory"
+	"github.com/stepupdream/go-support-tool/file"
+)
+
+// TableRegistry is the set of tables found in the operation directories. (ex. insert)
+// Unlike NewTabular, the table names do not need to be known in advance.
+type TableRegistry struct {
+	extensionName  string
+	isPartialMatch bool
+	// Schemas is the schema of each table by name. A table without a schema is loaded without validation.
+	Schemas    map[string]*Schema
+	UpdateMode UpdateMode
+	ErrorMode  ErrorMode
+	// Operations is the operations that the tables apply. If nil, DefaultOperationRegistry is used.
+	Operations *OperationRegistry
+	Tables     map[string]*MasterData
+}
+
+// NewTableRegistry Create a new TableRegistry without tables.
+//
+//goland:noinspection GoUnusedExportedFunction
+func NewTableRegistry(extensionName string, isPartialMatch bool) *TableRegistry {
+	return &TableRegistry{
+		extensionName:  extensionName,
+		isPartialMatch: isPartialMatch,
+		Schemas:        make(map[string]*Schema),
+		Tables:         make(map[string]*MasterData),
+	}
+}
+
+// Names Get the names of the registered tables in ascending order.
+func (r *TableRegistry) Names() []string {
+	names := make([]string, 0, len(r.Tables))
+	for tableName := range r.Tables {
+		names = append(names, tableName)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Discover Find the names of the tables that are not registered yet in the operation directories of the directory path.
+// With partial match, a file whose name starts with the name of another table belongs to that table,
+// so the files items.csv and items_2.csv are the table items.
+// A found name that is a prefix of a registered table is an error, since the files of both tables would match it.
+// (ex. items.csv in a version after items_2.csv was registered as the table items_2)
+func (r *TableRegistry) Discover(directoryPath string) ([]string, error) {
+	pathSeparator := string(os.PathSeparator)
+	found := make(map[string]bool)
+	for _, loadType := range r.operationRegistry().Names() {
+		loadTypePath := directoryPath + pathSeparator + loadType + pathSeparator
+		if !directory.Exist(loadTypePath) {
+			continue
+		}
+
+		filePaths, err := directory.GetFilePathRecursive(loadTypePath, []string{"." + r.extensionName})
+		if err != nil {
+			return nil, err
+		}
+		for _, filePath := range filePaths {
+			found[file.BaseFileName(filePath)] = true
+		}
+	}
+
+	baseFileNames := make([]string, 0, len(found))
+	for baseFileName := range found {
+		baseFileNames = append(baseFileNames, baseFileName)
+	}
+	// A name comes before the names it is a prefix of, so the shortest name becomes the table.
+	sort.Strings(baseFileNames)
+
+	var names []string
+	for _, baseFileName := range baseFileNames {
+		if r.tableNameOf(baseFileName, names) != "" {
+			continue
+		}
+		for _, tableName := range r.Names() {
+			if matchTableName(baseFileName, tableName, r.isPartialMatch) {
+				return nil, errors.New("Table name is a prefix of a registered table : " + baseFileName + " " + tableName)
+			}
+		}
+		names = append(names, baseFileName)
+	}
+
+	return names, nil
+}
+
+// tableNameOf Get the name of the registered or discovered table that the file of the base file name belongs to.
+// If there is no such table, return an empty string.
+func (r *TableRegistry) tableNameOf(baseFileName string, names []string) string {
+	for tableName := range r.Tables {
+		if matchTableName(tableName, baseFileName, r.isPartialMatch) {
+			return tableName
+		}
+	}
+	for _, tableName := range names {
+		if matchTableName(tableName, baseFileName, r.isPartialMatch) {
+			return tableName
+		}
+	}
+
+	return ""
+}
+
+// LoadByDirectoryPath Register the tables found in the directory path and load the directory into every table.
+// See MasterData.LoadByDirectoryPath for the layout of the directory.
+// The load is all-or-nothing. If a table fails, every table is restored and the found tables are not registered.
+func (r *TableRegistry) LoadByDirectoryPath(directoryPath string) error {
+	names, err := r.Discover(directoryPath)
+	if err != nil {
+		return err
+	}
+	tables := make(map[string]*MasterData, len(r.Tables)+len(names))
+	for tableName, m := range r.Tables {
+		tables[tableName] = m
+	}
+	for _, tableName := range names {
+		tables[tableName] = r.newTable(tableName)
+	}
+
+	tableNames := make([]string, 0, len(tables))
+	for tableName := range tables {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+
+	var errs TableErrors
+	for _, tableName := range tableNames {
+		tables[tableName].begin()
+		if err = tables[tableName].load(directoryPath); err != nil {
+			errs = append(errs, &TableError{Table: tableName, Err: err})
+		}
+	}
+	for _, tableName := range tableNames {
+		if len(errs) > 0 {
+			tables[tableName].rollback()
+		} else {
+			tables[tableName].commit()
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	for _, tableName := range names {
+		r.Tables[tableName] = tables[tableName]
+	}
+
+	return nil
+}
+
+// LoadByVersionRange Load the version directories from fromVersion to toVersion in numeric order.
+// A table that first appears in a later version is registered when the version is loaded.
+func (r *TableRegistry) LoadByVersionRange(rootDirectoryPath string, fromVersion string, toVersion string) error {
+	return r.eachVersion(rootDirectoryPath, fromVersion, toVersion, func(version string) error {
+		return nil
+	})
+}
+
+// eachVersion Load the version directories in order, and call the function after each version is loaded.
+func (r *TableRegistry) eachVersion(rootDirectoryPath string, fromVersion string, toVersion string, function func(version string) error) error {
+	versions, err := VersionNames(rootDirectoryPath, fromVersion, toVersion)
+	if err != nil {
+		return err
+	}
+
+	pathSeparator := string(os.PathSeparator)
+	for _, version := range versions {
+		if err = r.LoadByDirectoryPath(rootDirectoryPath + pathSeparator + version); err != nil {
+			return &VersionError{Version: version, Err: err}
+		}
+		if err = function(version); err != nil {
+			return &VersionError{Version: version, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// newTable Create an empty table with the settings of the registry.
+func (r *TableRegistry) newTable(name string) *MasterData {
+	m := NewTabular(name, r.extensionName, make(map[Key]string), r.isPartialMatch)
+	m.SetSchema(r.Schemas[name])
+	m.SetUpdateMode(r.UpdateMode)
+	m.SetErrorMode(r.ErrorMode)
+	m.SetOperationRegistry(r.Operations)
+
+	return m
+}
+
+// operationRegistry Get the operations that the tables apply.
+func (r *TableRegistry) operationRegistry() *OperationRegistry {
+	if r.Operations == nil {
+		return DefaultOperationRegistry
+	}
+
+	return r.Operations
+}
@@ -0,0 +1,81 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTableRegistry_Discover(t *testing.T) {
+	tests := []struct {
+		name           string
+		isPartialMatch bool
+		want           []string
+	}{
+		{name: "Discover1", isPartialMatch: true, want: []string{"items"}},
+		{name: "Discover2", isPartialMatch: false, want: []string{"items", "items_2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTableRegistry("csv", tt.isPartialMatch).Discover("./testdata/registry/1_0_0_0")
+			if err != nil {
+				t.Errorf("Discover() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Discover() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableRegistry_LoadByVersionRange(t *testing.T) {
+	r := NewTableRegistry("csv", true)
+	if err := r.LoadByVersionRange("./testdata/registry", "", ""); err != nil {
+		t.Errorf("LoadByVersionRange() error = %v", err)
+		return
+	}
+
+	if got, want := r.Names(), []string{"items", "weapons"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() got = %v, want %v", got, want)
+	}
+	want := map[Key]string{
+		{Id: 1, Key: "id"}:   "1",
+		{Id: 1, Key: "name"}: "long sword",
+		{Id: 2, Key: "id"}:   "2",
+		{Id: 2, Key: "name"}: "shield",
+	}
+	if !reflect.DeepEqual(r.Tables["items"].Rows, want) {
+		t.Errorf("LoadByVersionRange() got = %v, want %v", r.Tables["items"].Rows, want)
+	}
+}
+
+func TestTableRegistry_LoadByVersionRange_Error(t *testing.T) {
+	r := NewTableRegistry("csv", false)
+	if err := r.LoadByVersionRange("./testdata/registry_error", "", ""); err == nil {
+		t.Errorf("LoadByVersionRange() error = nil, want error")
+		return
+	}
+
+	if got, want := r.Names(), []string{"items"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() got = %v, want %v", got, want)
+	}
+	want := map[Key]string{
+		{Id: 1, Key: "id"}:   "1",
+		{Id: 1, Key: "name"}: "sword",
+	}
+	if !reflect.DeepEqual(r.Tables["items"].Rows, want) {
+		t.Errorf("LoadByVersionRange() got = %v, want %v", r.Tables["items"].Rows, want)
+	}
+}
+
+func TestTableRegistry_LoadByVersionRange_Prefix(t *testing.T) {
+	r := NewTableRegistry("csv", true)
+	if err := r.LoadByVersionRange("./testdata/registry_prefix", "", ""); err == nil {
+		t.Errorf("LoadByVersionRange() error = nil, want error of the prefix of a registered table")
+		return
+	}
+
+	if got, want := r.Names(), []string{"items_2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,339 @@
+package table
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/go-support-tool/array"
+	"github.com/stepupdream/go-support-tool/delimited"
+)
+
+// ColumnType is the type of the value stored in a column.
+type ColumnType int
+
+const (
+	TypeString ColumnType = iota
+	TypeInt
+	TypeFloat
+	TypeBool
+	TypeEnum
+	TypeDate
+)
+
+// DateLayout is the layout used for date columns when no layout is specified.
+const DateLayout = "2006-01-02 15:04:05"
+
+// EnumSeparator is the separator of the enum values in the schema file.
+const EnumSeparator = "|"
+
+var columnTypeNames = map[ColumnType]string{
+	TypeString: "string",
+	TypeInt:    "int",
+	TypeFloat:  "float",
+	TypeBool:   "bool",
+	TypeEnum:   "enum",
+	TypeDate:   "date",
+}
+
+// String returns the name of the column type.
+func (t ColumnType) String() string {
+	return columnTypeNames[t]
+}
+
+// EmptyPolicy decides how an empty cell is handled.
+type EmptyPolicy int
+
+const (
+	// EmptyInherit uses the policy of the table. For a column, a nullable column stores an empty string.
+	EmptyInherit EmptyPolicy = iota
+	// EmptyReject rejects an empty cell.
+	EmptyReject
+	// EmptyAsString stores an empty cell as an empty string.
+	EmptyAsString
+	// EmptyAsAbsent does not store an empty cell, so the key does not exist in the map.
+	EmptyAsAbsent
+	// EmptyAsDefault stores the default value of the column instead of an empty cell.
+	EmptyAsDefault
+)
+
+var emptyPolicyNames = map[EmptyPolicy]string{
+	EmptyInherit:   "",
+	EmptyReject:    "reject",
+	EmptyAsString:  "string",
+	EmptyAsAbsent:  "absent",
+	EmptyAsDefault: "default",
+}
+
+// Column is the definition of a column of a table.
+type Column struct {
+	Name     string
+	Type     ColumnType
+	Nullable bool
+	// Values is the list of values allowed in an enum column.
+	Values []string
+	// Layout is the layout of a date column. If empty, DateLayout is used.
+	Layout string
+	// Reference is the name of the table whose key column the value of this column refers to.
+	Reference string
+	// Empty is the policy for an empty cell of this column.
+	Empty EmptyPolicy
+	// Default is the value stored instead of an empty cell when the policy is EmptyAsDefault.
+	Default string
+}
+
+// Schema is the definition of the columns of a table.
+// Columns that are not defined in the schema are not validated.
+type Schema struct {
+	Columns []Column
+	// KeyColumns is the list of columns that identify a row. If empty, DefaultKeyColumns is used.
+	KeyColumns []string
+	// Empty is the policy for an empty cell of the columns that do not have their own policy. If not set, it is rejected.
+	Empty EmptyPolicy
+	// NullMarker is the text that explicitly marks a cell as having no value. (ex. NULL)
+	// The cell is not stored, and in an update file the current value of the column is removed.
+	// It is allowed only for the columns whose empty policy is not EmptyReject.
+	NullMarker string
+	// Window is the columns of the validity window of the rows. If nil, the rows have no window.
+	Window *TimeWindow
+}
+
+// ParseColumnType Parse the type name of a column. A type name ending with "?" is nullable. (ex. int?)
+func ParseColumnType(typeName string) (columnType ColumnType, nullable bool, err error) {
+	nullable = strings.HasSuffix(typeName, "?")
+	typeName = strings.TrimSuffix(typeName, "?")
+
+	for columnType, name := range columnTypeNames {
+		if name == typeName {
+			return columnType, nullable, nil
+		}
+	}
+
+	return TypeString, false, errors.New("Unknown column type : " + typeName)
+}
+
+// LoadSchema Load the schema from the specified file.
+// The file must have the name and type columns, and can have the values, layout, reference, empty, default, key and window columns.
+// The empty column is one of reject, string, absent and default.
+// The window column is one of start, end and group, and declares the column as a part of the validity window.
+// The columns whose key is true become the key columns in the order of the file.
+// ex. name,type,values,key
+//
+//	id,int,,true
+//	rarity,enum,N|R|SR,false
+//
+//goland:noinspection GoUnusedExportedFunction
+func LoadSchema(filePath string) (*Schema, error) {
+	rows, err := delimited.Load(filePath, true, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("Empty schema file : " + filePath)
+	}
+
+	headers := rows[0]
+	if !array.Contains(headers, "name") || !array.Contains(headers, "type") {
+		return nil, errors.New("Not found name or type column : " + filePath)
+	}
+
+	schema := &Schema{}
+	for rowNumber, row := range rows[1:] {
+		values := map[string]string{}
+		for columnNumber, value := range row {
+			values[headers[columnNumber]] = value
+		}
+
+		columnType, nullable, err := ParseColumnType(values["type"])
+		if err != nil {
+			return nil, errors.Wrap(err, filePath+" rowNumber : "+strconv.Itoa(rowNumber+1))
+		}
+
+		column := Column{
+			Name:      values["name"],
+			Type:      columnType,
+			Nullable:  nullable,
+			Layout:    values["layout"],
+			Reference: values["reference"],
+			Default:   values["default"],
+		}
+		if column.Empty, err = ParseEmptyPolicy(values["empty"]); err != nil {
+			return nil, errors.Wrap(err, filePath+" rowNumber : "+strconv.Itoa(rowNumber+1))
+		}
+		if values["values"] != "" {
+			column.Values = strings.Split(values["values"], EnumSeparator)
+		}
+		schema.Columns = append(schema.Columns, column)
+
+		if err = schema.addWindowColumn(column.Name, values["window"]); err != nil {
+			return nil, errors.Wrap(err, filePath+" rowNumber : "+strconv.Itoa(rowNumber+1))
+		}
+
+		if values["key"] != "" {
+			isKey, err := strconv.ParseBool(values["key"])
+			if err != nil {
+				return nil, errors.New("Key is not bool : " + filePath + " rowNumber : " + strconv.Itoa(rowNumber+1))
+			}
+			if isKey {
+				schema.KeyColumns = append(schema.KeyColumns, column.Name)
+			}
+		}
+	}
+
+	return schema, nil
+}
+
+// addWindowColumn Add the column to the validity window by the role of the column. (start, end or group)
+func (s *Schema) addWindowColumn(columnName string, role string) error {
+	if role == "" {
+		return nil
+	}
+	if s.Window == nil {
+		s.Window = &TimeWindow{}
+	}
+
+	switch role {
+	case "start":
+		s.Window.StartColumn = columnName
+	case "end":
+		s.Window.EndColumn = columnName
+	case "group":
+		s.Window.GroupColumns = append(s.Window.GroupColumns, columnName)
+	default:
+		return errors.New("Unknown window role : " + role)
+	}
+
+	return nil
+}
+
+// ParseEmptyPolicy Parse the name of an empty policy. An empty name is EmptyInherit.
+func ParseEmptyPolicy(policyName string) (EmptyPolicy, error) {
+	for emptyPolicy, name := range emptyPolicyNames {
+		if name == policyName {
+			return emptyPolicy, nil
+		}
+	}
+
+	return EmptyInherit, errors.New("Unknown empty policy : " + policyName)
+}
+
+// Column Get the definition of the specified column.
+func (s *Schema) Column(name string) (Column, bool) {
+	for _, column := range s.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+
+	return Column{}, false
+}
+
+// Validate Check that the value matches the type of the column.
+// An empty value is valid only for a nullable column.
+func (c Column) Validate(value string) error {
+	if value == "" {
+		if c.Nullable {
+			return nil
+		}
+		return errors.New("Empty value")
+	}
+
+	var err error
+	switch c.Type {
+	case TypeInt:
+		_, err = strconv.Atoi(value)
+	case TypeFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	case TypeBool:
+		_, err = strconv.ParseBool(value)
+	case TypeEnum:
+		if !array.Contains(c.Values, value) {
+			err = errors.New("not in " + strings.Join(c.Values, EnumSeparator))
+		}
+	case TypeDate:
+		_, err = time.Parse(c.layout(), value)
+	}
+
+	if err != nil {
+		return errors.New("Value is not " + c.Type.String() + " : " + value)
+	}
+
+	return nil
+}
+
+// layout returns the layout of a date column.
+func (c Column) layout() string {
+	if c.Layout == "" {
+		return DateLayout
+	}
+
+	return c.Layout
+}
+
+// isNull Check if the value means no value in the column.
+// An empty value of a nullable column is no value, and so is one of a column typed other than string,
+// which can be stored by EmptyAsString even when the column is not nullable.
+func (c Column) isNull(value string) bool {
+	return value == "" && (c.Nullable || c.Type != TypeString)
+}
+
+// emptyPolicy Get the policy for an empty cell of the column.
+func (s *Schema) emptyPolicy(columnName string) EmptyPolicy {
+	if s == nil {
+		return EmptyReject
+	}
+
+	if column, ok := s.Column(columnName); ok {
+		if column.Empty != EmptyInherit {
+			return column.Empty
+		}
+		if column.Nullable {
+			return EmptyAsString
+		}
+	}
+	if s.Empty != EmptyInherit {
+		return s.Empty
+	}
+
+	return EmptyReject
+}
+
+// IsRequired Check if every row has a value of the column that is not null.
+// A key column is always required. Another declared column is required when its empty cell is rejected,
+// or is stored as a value that is not null (an empty string of a string column or the default) and the null marker cannot clear it.
+func (s *Schema) IsRequired(columnName string) bool {
+	if array.Contains(s.keyColumns(), columnName) {
+		return true
+	}
+	if s == nil {
+		return false
+	}
+	column, declared := s.Column(columnName)
+	if !declared {
+		return false
+	}
+
+	switch s.emptyPolicy(columnName) {
+	case EmptyReject:
+		return true
+	case EmptyAsString:
+		return s.NullMarker == "" && !column.isNull("")
+	case EmptyAsDefault:
+		return s.NullMarker == "" && !column.isNull(column.Default)
+	}
+
+	return false
+}
+
+// defaultValue Get the value stored instead of an empty cell of the column.
+func (s *Schema) defaultValue(columnName string) string {
+	column, _ := s.Column(columnName)
+
+	return column.Default
+}
+
+// isNullMarker Check if the value is the null marker of the table.
+func (s *Schema) isNullMarker(value string) bool {
+	return s != nil && s.NullMarker != "" && value == s.NullMarker
+}
@@ -0,0 +1,238 @@
+package table
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func testSchema() *Schema {
+	return &Schema{
+		Columns: []Column{
+			{Name: "id", Type: TypeInt},
+			{Name: "name", Type: TypeString},
+			{Name: "rarity", Type: TypeEnum, Values: []string{"N", "R", "SR"}},
+			{Name: "rate", Type: TypeFloat, Nullable: true},
+			{Name: "is_limited", Type: TypeBool},
+			{Name: "start_at", Type: TypeDate},
+		},
+		KeyColumns: []string{"id"},
+	}
+}
+
+func TestParseColumnType(t *testing.T) {
+	tests := []struct {
+		name         string
+		typeName     string
+		want         ColumnType
+		wantNullable bool
+		wantErr      bool
+	}{
+		{name: "ParseColumnType1", typeName: "int", want: TypeInt, wantNullable: false, wantErr: false},
+		{name: "ParseColumnType2", typeName: "date?", want: TypeDate, wantNullable: true, wantErr: false},
+		{name: "ParseColumnType3", typeName: "integer", want: TypeString, wantNullable: false, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotNullable, err := ParseColumnType(tt.typeName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseColumnType() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want || gotNullable != tt.wantNullable {
+				t.Errorf("ParseColumnType() got = %v %v, want %v %v", got, gotNullable, tt.want, tt.wantNullable)
+			}
+		})
+	}
+}
+
+func TestLoadSchema(t *testing.T) {
+	got, err := LoadSchema("./testdata/schema/items.csv")
+	if err != nil {
+		t.Errorf("LoadSchema() error = %v", err)
+		return
+	}
+	if !reflect.DeepEqual(got, testSchema()) {
+		t.Errorf("LoadSchema() got = %v, want %v", got, testSchema())
+	}
+}
+
+func TestColumn_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		column  Column
+		value   string
+		wantErr bool
+	}{
+		{name: "Validate1", column: Column{Type: TypeInt}, value: "12", wantErr: false},
+		{name: "Validate2", column: Column{Type: TypeInt}, value: "1.5", wantErr: true},
+		{name: "Validate3", column: Column{Type: TypeFloat}, value: "1.5", wantErr: false},
+		{name: "Validate4", column: Column{Type: TypeBool}, value: "true", wantErr: false},
+		{name: "Validate5", column: Column{Type: TypeEnum, Values: []string{"a", "b"}}, value: "c", wantErr: true},
+		{name: "Validate6", column: Column{Type: TypeDate, Layout: "2006/01/02"}, value: "2023/04/01", wantErr: false},
+		{name: "Validate7", column: Column{Type: TypeString}, value: "", wantErr: true},
+		{name: "Validate8", column: Column{Type: TypeInt, Nullable: true}, value: "", wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.column.Validate(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadMapWithSchema(t *testing.T) {
+	got, err := LoadMapWithSchema("./testdata/schema_valid/insert/items.csv", testSchema())
+	if err != nil {
+		t.Errorf("LoadMapWithSchema() error = %v", err)
+		return
+	}
+	if got[Key{Id: 2, Key: "rate"}] != "" || len(got) != 12 {
+		t.Errorf("LoadMapWithSchema() got = %v", got)
+	}
+
+	_, err = LoadMapWithSchema("./testdata/schema_error/insert/items.csv", testSchema())
+	var validationErrors ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		t.Errorf("LoadMapWithSchema() error = %v, want ValidationErrors", err)
+		return
+	}
+	want := ValidationErrors{
+		{Kind: KindInvalidValue, FilePath: "./testdata/schema_error/insert/items.csv", Line: 3, RowNumber: 2, Id: "2", Column: "rarity", Value: "UR", Message: "Value is not enum : UR"},
+		{Kind: KindInvalidValue, FilePath: "./testdata/schema_error/insert/items.csv", Line: 3, RowNumber: 2, Id: "2", Column: "rate", Value: "x", Message: "Value is not float : x"},
+		{Kind: KindInvalidValue, FilePath: "./testdata/schema_error/insert/items.csv", Line: 3, RowNumber: 2, Id: "2", Column: "is_limited", Value: "yes", Message: "Value is not bool : yes"},
+		{Kind: KindInvalidValue, FilePath: "./testdata/schema_error/insert/items.csv", Line: 3, RowNumber: 2, Id: "2", Column: "start_at", Value: "2023-01-01", Message: "Value is not date : 2023-01-01"},
+	}
+	if !reflect.DeepEqual(validationErrors, want) {
+		t.Errorf("LoadMapWithSchema() error = %v, want %v", validationErrors, want)
+	}
+}
+
+func TestLoadByDirectoryPathWithSchema(t *testing.T) {
+	m := NewTabular("items", "csv", map[Key]string{}, false)
+	m.SetSchema(testSchema())
+
+	if err := m.LoadByDirectoryPath("./testdata/schema_valid"); err != nil {
+		t.Errorf("LoadByDirectoryPath() error = %v", err)
+	}
+
+	m = NewTabular("items", "csv", map[Key]string{}, false)
+	m.SetSchema(testSchema())
+	err := m.LoadByDirectoryPath("./testdata/schema_error")
+	var validationErrors ValidationErrors
+	if !errors.As(err, &validationErrors) || len(validationErrors) != 6 {
+		t.Errorf("LoadByDirectoryPath() error = %v, want 6 ValidationErrors", err)
+	}
+}
+
+func testEmptySchema() *Schema {
+	return &Schema{
+		Columns: []Column{
+			{Name: "name", Type: TypeString, Empty: EmptyReject},
+			{Name: "rate", Type: TypeFloat, Empty: EmptyAsDefault, Default: "1.0"},
+			{Name: "level", Type: TypeInt, Nullable: true},
+		},
+		Empty:      EmptyAsAbsent,
+		NullMarker: "NULL",
+	}
+}
+
+func TestSchema_IsRequired(t *testing.T) {
+	tests := []struct {
+		name       string
+		schema     *Schema
+		columnName string
+		want       bool
+	}{
+		{name: "IsRequired1", schema: testEmptySchema(), columnName: "id", want: true},
+		{name: "IsRequired2", schema: testEmptySchema(), columnName: "name", want: true},
+		{name: "IsRequired3", schema: testEmptySchema(), columnName: "rate", want: false},
+		{name: "IsRequired4", schema: testEmptySchema(), columnName: "level", want: false},
+		{name: "IsRequired5", schema: testEmptySchema(), columnName: "memo", want: false},
+		{name: "IsRequired6", schema: &Schema{Columns: []Column{{Name: "rate", Type: TypeFloat, Empty: EmptyAsDefault, Default: "1.0"}}}, columnName: "rate", want: true},
+		{name: "IsRequired7", schema: &Schema{Columns: []Column{{Name: "level", Type: TypeInt}}, Empty: EmptyAsString}, columnName: "level", want: false},
+		{name: "IsRequired8", schema: nil, columnName: "id", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schema.IsRequired(tt.columnName); got != tt.want {
+				t.Errorf("IsRequired() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMapWithSchema_EmptyPolicy(t *testing.T) {
+	got, err := LoadMapWithSchema("./testdata/empty/insert/items.csv", testEmptySchema())
+	if err != nil {
+		t.Errorf("LoadMapWithSchema() error = %v", err)
+		return
+	}
+	want := map[Key]string{
+		{Id: 1, Key: "id"}:    "1",
+		{Id: 1, Key: "name"}:  "sword",
+		{Id: 1, Key: "rate"}:  "1.0",
+		{Id: 1, Key: "level"}: "",
+		{Id: 2, Key: "id"}:    "2",
+		{Id: 2, Key: "name"}:  "shield",
+		{Id: 2, Key: "memo"}:  "note",
+		{Id: 2, Key: "level"}: "5",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadMapWithSchema() got = %v, want %v", got, want)
+	}
+
+	_, err = LoadMapWithSchema("./testdata/empty_error/insert/items.csv", testEmptySchema())
+	var validationErrors ValidationErrors
+	if !errors.As(err, &validationErrors) || len(validationErrors) != 2 || validationErrors[1].Message != "NULL is not allowed" {
+		t.Errorf("LoadMapWithSchema() error = %v, want 2 ValidationErrors", err)
+	}
+}
+
+func TestLoadByDirectoryPath_NullMarker(t *testing.T) {
+	m := NewTabular("items", "csv", map[Key]string{
+		{Id: 2, Key: "id"}:    "2",
+		{Id: 2, Key: "name"}:  "shield",
+		{Id: 2, Key: "memo"}:  "note",
+		{Id: 2, Key: "level"}: "5",
+	}, false)
+	m.SetSchema(testEmptySchema())
+	m.EnableProvenance()
+
+	if err := m.LoadByDirectoryPath("./testdata/null"); err != nil {
+		t.Errorf("LoadByDirectoryPath() error = %v", err)
+		return
+	}
+	want := map[Key]string{
+		{Id: 2, Key: "id"}:    "2",
+		{Id: 2, Key: "name"}:  "shield",
+		{Id: 2, Key: "level"}: "7",
+	}
+	if !reflect.DeepEqual(m.Rows, want) {
+		t.Errorf("LoadByDirectoryPath() got = %v, want %v", m.Rows, want)
+	}
+	if _, ok := m.Provenance(Key{Id: 2, Key: "memo"}); ok {
+		t.Errorf("Provenance() of a cleared value exists")
+	}
+}
+
+func TestParseEmptyPolicy(t *testing.T) {
+	tests := []struct {
+		policyName string
+		want       EmptyPolicy
+		wantErr    bool
+	}{
+		{policyName: "", want: EmptyInherit, wantErr: false},
+		{policyName: "absent", want: EmptyAsAbsent, wantErr: false},
+		{policyName: "null", want: EmptyInherit, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policyName, func(t *testing.T) {
+			got, err := ParseEmptyPolicy(tt.policyName)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseEmptyPolicy() = %v, error = %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,399 @@
+package table
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/go-support-tool/array"
+)
+
+// Dialect is the SQL dialect of the generated statements.
+type Dialect int
+
+const (
+	DialectMySQL Dialect = iota
+	DialectSQLite
+)
+
+// DefaultBatchSize is the number of rows of an INSERT statement when the batch size is not specified.
+const DefaultBatchSize = 500
+
+// sqlDateLayout is the layout of the date literals, which both MySQL and SQLite read as a date and time.
+const sqlDateLayout = "2006-01-02 15:04:05"
+
+// CreateTableSQL Generate the CREATE TABLE statement of the table from the columns and the schema.
+// A column that is not in the schema is a nullable text column, and the key columns are the primary key.
+// A column is NOT NULL only when Schema.IsRequired, since its empty policy can otherwise leave a row without a value.
+func (m *MasterData) CreateTableSQL(dialect Dialect) string {
+	keyColumns := m.schema.keyColumns()
+
+	var definitions []string
+	for _, column := range m.Columns() {
+		definition := "  " + quoteIdentifier(dialect, column) + " " + m.sqlType(dialect, column)
+		if m.schema.IsRequired(column) {
+			definition += " NOT NULL"
+		}
+		definitions = append(definitions, definition)
+	}
+
+	quotedKeys := make([]string, 0, len(keyColumns))
+	for _, column := range keyColumns {
+		quotedKeys = append(quotedKeys, quoteIdentifier(dialect, column))
+	}
+	definitions = append(definitions, "  PRIMARY KEY ("+strings.Join(quotedKeys, ", ")+")")
+
+	return "CREATE TABLE " + quoteIdentifier(dialect, m.name) + " (\n" + strings.Join(definitions, ",\n") + "\n);\n"
+}
+
+// InsertSQL Generate the INSERT statements of all rows of the table, batchSize rows per statement.
+// If batchSize is 0 or less, DefaultBatchSize is used. A missing value is NULL.
+func (m *MasterData) InsertSQL(dialect Dialect, batchSize int) (string, error) {
+	columns := m.Columns()
+	var values []string
+	for _, id := range m.RowIds() {
+		value, err := m.sqlValues(dialect, columns, m.Rows, id)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, value)
+	}
+
+	return m.insertStatements(dialect, columns, values, batchSize), nil
+}
+
+// DeltaSQL Generate the statements that apply the operation files of the directory path to the table.
+// The directory is checked with ValidateDirectoryPath first, and the table is not changed.
+// The statements are in the order the files are applied. A custom operation is not supported.
+// An update sets only the columns in the update file, and a value cleared by the null marker becomes NULL.
+// An upsert is an update of the rows that exist and an insert of the others, and a replace deletes every row first.
+// The INSERT statements have batchSize rows per statement. If batchSize is 0 or less, DefaultBatchSize is used.
+func (m *MasterData) DeltaSQL(dialect Dialect, directoryPath string, batchSize int) (string, error) {
+	if err := m.ValidateDirectoryPath(directoryPath); err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	isReplaced := false
+	for _, loadType := range m.operationRegistry().Names() {
+		filePaths, err := m.editFilePaths(directoryPath, loadType)
+		if err != nil {
+			return "", err
+		}
+
+		for _, filePath := range filePaths {
+			editFile, err := loadFile(filePath, m.schema)
+			if err != nil {
+				return "", err
+			}
+
+			var statements string
+			switch loadType {
+			case "replace":
+				if !isReplaced {
+					builder.WriteString("DELETE FROM " + quoteIdentifier(dialect, m.name) + ";\n")
+				}
+				isReplaced = true
+				statements, err = m.insertFileSQL(dialect, editFile, batchSize)
+			case "upsert":
+				statements, err = m.upsertSQL(dialect, editFile, isReplaced, batchSize)
+			case "delete":
+				statements, err = m.deleteSQL(dialect, editFile)
+			case "update":
+				statements, err = m.updateSQL(dialect, editFile)
+			case "insert":
+				statements, err = m.insertFileSQL(dialect, editFile, batchSize)
+			default:
+				err = errors.New("SQL is not supported for operation : " + loadType)
+			}
+			if err != nil {
+				return "", errors.Wrap(err, filePath)
+			}
+			builder.WriteString(statements)
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// ExportSQL Write the CREATE TABLE statement and the INSERT statements of the table to the specified file.
+func (m *MasterData) ExportSQL(path string, dialect Dialect, batchSize int) error {
+	insertSQL, err := m.InsertSQL(dialect, batchSize)
+	if err != nil {
+		return err
+	}
+
+	return exportFile(path, func(writer io.Writer) error {
+		_, err := io.WriteString(writer, m.CreateTableSQL(dialect)+insertSQL)
+		return err
+	})
+}
+
+// deleteSQL Generate a DELETE statement for each row of the delete file.
+func (m *MasterData) deleteSQL(dialect Dialect, editFile *EditFile) (string, error) {
+	var builder strings.Builder
+	for _, id := range PluckRowId(editFile.rows) {
+		where, err := m.whereKey(dialect, editFile.rows, id)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString("DELETE FROM " + quoteIdentifier(dialect, m.name) + " WHERE " + where + ";\n")
+	}
+
+	return builder.String(), nil
+}
+
+// updateSQL Generate an UPDATE statement for each row of the update file.
+func (m *MasterData) updateSQL(dialect Dialect, editFile *EditFile) (string, error) {
+	keyColumns := m.schema.keyColumns()
+
+	var builder strings.Builder
+	for _, id := range PluckRowId(editFile.rows) {
+		var assignments []string
+		for _, column := range editFile.headers {
+			if array.Contains(keyColumns, column) {
+				continue
+			}
+			key := id.Key(column)
+			value, ok := editFile.rows[key]
+			if !ok && !editFile.cleared[key] {
+				continue
+			}
+			literal, err := m.sqlLiteral(dialect, column, value, ok)
+			if err != nil {
+				return "", errors.Wrap(err, "id : "+id.String())
+			}
+			assignments = append(assignments, quoteIdentifier(dialect, column)+" = "+literal)
+		}
+		if len(assignments) == 0 {
+			continue
+		}
+
+		where, err := m.whereKey(dialect, editFile.rows, id)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString("UPDATE " + quoteIdentifier(dialect, m.name) + " SET " + strings.Join(assignments, ", ") + " WHERE " + where + ";\n")
+	}
+
+	return builder.String(), nil
+}
+
+// upsertSQL Generate an UPDATE statement for each row of the upsert file that exists in the table, and INSERT statements of the others.
+// The rows do not exist after a replace, because the ids of a directory are unique.
+func (m *MasterData) upsertSQL(dialect Dialect, editFile *EditFile, isReplaced bool, batchSize int) (string, error) {
+	updateFile, insertFile := newEditFile(editFile.path), newEditFile(editFile.path)
+	updateFile.headers, insertFile.headers = editFile.headers, editFile.headers
+	for key, value := range editFile.rows {
+		if !isReplaced && m.HasRow(key.RowId()) {
+			updateFile.rows[key] = value
+		} else {
+			insertFile.rows[key] = value
+		}
+	}
+	for key := range editFile.cleared {
+		updateFile.cleared[key] = true
+	}
+
+	updateSQL, err := m.updateSQL(dialect, updateFile)
+	if err != nil {
+		return "", err
+	}
+	insertSQL, err := m.insertFileSQL(dialect, insertFile, batchSize)
+	if err != nil {
+		return "", err
+	}
+
+	return updateSQL + insertSQL, nil
+}
+
+// insertFileSQL Generate the INSERT statements of the rows of the insert file, batchSize rows per statement.
+func (m *MasterData) insertFileSQL(dialect Dialect, editFile *EditFile, batchSize int) (string, error) {
+	var values []string
+	for _, id := range PluckRowId(editFile.rows) {
+		value, err := m.sqlValues(dialect, editFile.headers, editFile.rows, id)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, value)
+	}
+
+	return m.insertStatements(dialect, editFile.headers, values, batchSize), nil
+}
+
+// insertStatements Join the values into INSERT statements of batchSize rows.
+func (m *MasterData) insertStatements(dialect Dialect, columns []string, values []string, batchSize int) string {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	quotedColumns := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quotedColumns = append(quotedColumns, quoteIdentifier(dialect, column))
+	}
+	prefix := "INSERT INTO " + quoteIdentifier(dialect, m.name) + " (" + strings.Join(quotedColumns, ", ") + ") VALUES\n"
+
+	var builder strings.Builder
+	for start := 0; start < len(values); start += batchSize {
+		end := start + batchSize
+		if end > len(values) {
+			end = len(values)
+		}
+		builder.WriteString(prefix + strings.Join(values[start:end], ",\n") + ";\n")
+	}
+
+	return builder.String()
+}
+
+// sqlValues Generate the parenthesized values of the row for an INSERT statement.
+func (m *MasterData) sqlValues(dialect Dialect, columns []string, rows map[Key]string, id RowId) (string, error) {
+	literals := make([]string, 0, len(columns))
+	for _, column := range columns {
+		value, ok := rows[id.Key(column)]
+		literal, err := m.sqlLiteral(dialect, column, value, ok)
+		if err != nil {
+			return "", errors.Wrap(err, "id : "+id.String())
+		}
+		literals = append(literals, literal)
+	}
+
+	return "(" + strings.Join(literals, ", ") + ")", nil
+}
+
+// whereKey Generate the condition that identifies the row by the key columns.
+func (m *MasterData) whereKey(dialect Dialect, rows map[Key]string, id RowId) (string, error) {
+	var conditions []string
+	for _, column := range m.schema.keyColumns() {
+		literal, err := m.sqlLiteral(dialect, column, rows[id.Key(column)], true)
+		if err != nil {
+			return "", errors.Wrap(err, "id : "+id.String())
+		}
+		conditions = append(conditions, quoteIdentifier(dialect, column)+" = "+literal)
+	}
+
+	return strings.Join(conditions, " AND "), nil
+}
+
+// sqlLiteral Convert the value into an SQL literal by the type of the column.
+// A missing value, and an empty value of a nullable column or of a column typed other than string, is NULL.
+// A date is written in sqlDateLayout, and a float that is not finite is an error since SQL has no literal for it.
+func (m *MasterData) sqlLiteral(dialect Dialect, columnName string, value string, exists bool) (string, error) {
+	column, declared := m.schemaColumn(columnName)
+	if !exists || (declared && column.isNull(value)) {
+		return "NULL", nil
+	}
+
+	switch m.columnType(columnName) {
+	case TypeInt:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return "", errors.New("Value is not int : " + columnName + " : " + value)
+		}
+		return value, nil
+	case TypeFloat:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", errors.New("Value is not float : " + columnName + " : " + value)
+		}
+		if math.IsNaN(number) || math.IsInf(number, 0) {
+			return "", errors.New("Value is not finite : " + columnName + " : " + value)
+		}
+		return strconv.FormatFloat(number, 'g', -1, 64), nil
+	case TypeBool:
+		boolValue, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", errors.New("Value is not bool : " + columnName + " : " + value)
+		}
+		if boolValue {
+			return "1", nil
+		}
+		return "0", nil
+	case TypeDate:
+		date, err := time.Parse(column.layout(), value)
+		if err != nil {
+			return "", errors.New("Value is not date : " + columnName + " : " + value)
+		}
+		return quoteString(dialect, date.Format(sqlDateLayout)), nil
+	}
+
+	return quoteString(dialect, value), nil
+}
+
+// sqlType Get the SQL type of the column.
+func (m *MasterData) sqlType(dialect Dialect, columnName string) string {
+	columnType := m.columnType(columnName)
+	if dialect == DialectSQLite {
+		switch columnType {
+		case TypeInt, TypeBool:
+			return "INTEGER"
+		case TypeFloat:
+			return "REAL"
+		}
+		return "TEXT"
+	}
+
+	switch columnType {
+	case TypeInt:
+		return "BIGINT"
+	case TypeFloat:
+		return "DOUBLE"
+	case TypeBool:
+		return "TINYINT(1)"
+	case TypeDate:
+		return "DATETIME"
+	case TypeEnum:
+		column, _ := m.schemaColumn(columnName)
+		values := make([]string, 0, len(column.Values))
+		for _, value := range column.Values {
+			values = append(values, quoteString(dialect, value))
+		}
+		return "ENUM(" + strings.Join(values, ", ") + ")"
+	}
+	// A key column must have a length to be the primary key in MySQL.
+	if array.Contains(m.schema.keyColumns(), columnName) {
+		return "VARCHAR(255)"
+	}
+
+	return "TEXT"
+}
+
+// columnType Get the type of the column. A column that is not in the schema is a string, except the numeric key column.
+func (m *MasterData) columnType(columnName string) ColumnType {
+	if column, ok := m.schemaColumn(columnName); ok {
+		return column.Type
+	}
+	if m.schema.isNumericKey() && m.schema.keyColumns()[0] == columnName {
+		return TypeInt
+	}
+
+	return TypeString
+}
+
+// schemaColumn Get the definition of the column in the schema of the table.
+func (m *MasterData) schemaColumn(columnName string) (Column, bool) {
+	if m.schema == nil {
+		return Column{}, false
+	}
+
+	return m.schema.Column(columnName)
+}
+
+// quoteIdentifier Quote the name of a table or a column.
+func quoteIdentifier(dialect Dialect, identifier string) string {
+	if dialect == DialectSQLite {
+		return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+	}
+
+	return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+}
+
+// quoteString Quote the text as a string literal. MySQL also treats the backslash as an escape character.
+func quoteString(dialect Dialect, value string) string {
+	if dialect == DialectMySQL {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
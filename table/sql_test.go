@@ -0,0 +1,235 @@
+package table
+
+import (
+	"testing"
+)
+
+func testSQLTable() *MasterData {
+	m := NewTabular("items", "csv", map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "sword", {Id: 1, Key: "rate"}: "1.5", {Id: 1, Key: "is_limited"}: "true",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: `it's \ ok`, {Id: 2, Key: "rate"}: "",
+	}, false)
+	m.SetSchema(&Schema{
+		Columns: []Column{
+			{Name: "id", Type: TypeInt},
+			{Name: "name", Type: TypeString},
+			{Name: "rate", Type: TypeFloat, Nullable: true},
+			{Name: "is_limited", Type: TypeBool, Nullable: true},
+		},
+	})
+	m.SetColumns([]string{"id", "name", "rate", "is_limited"})
+
+	return m
+}
+
+func TestMasterData_CreateTableSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		want    string
+	}{
+		{
+			name:    "CreateTableSQL1",
+			dialect: DialectMySQL,
+			want: "CREATE TABLE `items` (\n" +
+				"  `id` BIGINT NOT NULL,\n" +
+				"  `name` TEXT NOT NULL,\n" +
+				"  `rate` DOUBLE,\n" +
+				"  `is_limited` TINYINT(1),\n" +
+				"  PRIMARY KEY (`id`)\n);\n",
+		},
+		{
+			name:    "CreateTableSQL2",
+			dialect: DialectSQLite,
+			want: "CREATE TABLE \"items\" (\n" +
+				"  \"id\" INTEGER NOT NULL,\n" +
+				"  \"name\" TEXT NOT NULL,\n" +
+				"  \"rate\" REAL,\n" +
+				"  \"is_limited\" INTEGER,\n" +
+				"  PRIMARY KEY (\"id\")\n);\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testSQLTable().CreateTableSQL(tt.dialect); got != tt.want {
+				t.Errorf("CreateTableSQL() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMasterData_CreateTableSQL_EmptyPolicy(t *testing.T) {
+	m := NewTabular("items", "csv", map[Key]string{}, false)
+	m.SetSchema(&Schema{
+		Columns: []Column{
+			{Name: "id", Type: TypeInt},
+			{Name: "name", Type: TypeString},
+			{Name: "level", Type: TypeInt},
+			{Name: "memo", Type: TypeString, Empty: EmptyAsAbsent},
+			{Name: "rate", Type: TypeFloat, Empty: EmptyAsDefault, Default: "1.0"},
+			{Name: "code", Type: TypeString, Empty: EmptyReject},
+		},
+		Empty: EmptyAsString,
+	})
+	m.SetColumns([]string{"id", "name", "level", "memo", "rate", "code"})
+
+	want := "CREATE TABLE `items` (\n" +
+		"  `id` BIGINT NOT NULL,\n" +
+		"  `name` TEXT NOT NULL,\n" +
+		"  `level` BIGINT,\n" +
+		"  `memo` TEXT,\n" +
+		"  `rate` DOUBLE NOT NULL,\n" +
+		"  `code` TEXT NOT NULL,\n" +
+		"  PRIMARY KEY (`id`)\n);\n"
+	if got := m.CreateTableSQL(DialectMySQL); got != want {
+		t.Errorf("CreateTableSQL() got = %v, want %v", got, want)
+	}
+}
+
+func TestMasterData_InsertSQL(t *testing.T) {
+	tests := []struct {
+		name      string
+		dialect   Dialect
+		batchSize int
+		want      string
+	}{
+		{
+			name:    "InsertSQL1",
+			dialect: DialectMySQL,
+			want: "INSERT INTO `items` (`id`, `name`, `rate`, `is_limited`) VALUES\n" +
+				"(1, 'sword', 1.5, 1),\n" +
+				"(2, 'it''s \\\\ ok', NULL, NULL);\n",
+		},
+		{
+			name:      "InsertSQL2",
+			dialect:   DialectSQLite,
+			batchSize: 1,
+			want: "INSERT INTO \"items\" (\"id\", \"name\", \"rate\", \"is_limited\") VALUES\n" +
+				"(1, 'sword', 1.5, 1);\n" +
+				"INSERT INTO \"items\" (\"id\", \"name\", \"rate\", \"is_limited\") VALUES\n" +
+				"(2, 'it''s \\ ok', NULL, NULL);\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testSQLTable().InsertSQL(tt.dialect, tt.batchSize)
+			if err != nil {
+				t.Errorf("InsertSQL() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("InsertSQL() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMasterData_DeltaSQL(t *testing.T) {
+	m := testSQLTable()
+	got, err := m.DeltaSQL(DialectMySQL, "./testdata/sql", 0)
+	if err != nil {
+		t.Errorf("DeltaSQL() error = %v", err)
+		return
+	}
+	want := "DELETE FROM `items` WHERE `id` = 2;\n" +
+		"UPDATE `items` SET `name` = 'long sword', `rate` = NULL WHERE `id` = 1;\n" +
+		"INSERT INTO `items` (`id`, `name`, `rate`) VALUES\n" +
+		"(3, 'Bob''s', 2.5);\n"
+	if got != want {
+		t.Errorf("DeltaSQL() got = %v, want %v", got, want)
+	}
+	if len(m.Rows) != 7 {
+		t.Errorf("DeltaSQL() changed the table : %v", m.Rows)
+	}
+
+	m.Rows[Key{Id: 3, Key: "id"}] = "3"
+	if _, err = m.DeltaSQL(DialectMySQL, "./testdata/sql", 0); err == nil {
+		t.Errorf("DeltaSQL() error = nil, want error")
+	}
+}
+
+func TestMasterData_DeltaSQL_UpsertReplace(t *testing.T) {
+	got, err := testSQLTable().DeltaSQL(DialectSQLite, "./testdata/sql_upsert", 0)
+	if err != nil {
+		t.Errorf("DeltaSQL() error = %v", err)
+		return
+	}
+	want := "UPDATE \"items\" SET \"name\" = 'axe' WHERE \"id\" = 1;\n" +
+		"INSERT INTO \"items\" (\"id\", \"name\") VALUES\n" +
+		"(4, 'bow');\n"
+	if got != want {
+		t.Errorf("DeltaSQL() got = %v, want %v", got, want)
+	}
+
+	m := NewTabular("samples", "csv", map[Key]string{{Id: 1, Key: "id"}: "1"}, false)
+	got, err = m.DeltaSQL(DialectMySQL, "./testdata/replace", 0)
+	if err != nil {
+		t.Errorf("DeltaSQL() error = %v", err)
+		return
+	}
+	want = "DELETE FROM `samples`;\n" +
+		"INSERT INTO `samples` (`id`, `sample`) VALUES\n" +
+		"(2, 'bbb');\n" +
+		"INSERT INTO `samples` (`id`, `sample`) VALUES\n" +
+		"(4, 'ddd');\n"
+	if got != want {
+		t.Errorf("DeltaSQL() got = %v, want %v", got, want)
+	}
+}
+
+func TestMasterData_DeltaSQL_BatchSize(t *testing.T) {
+	got, err := testSQLTable().DeltaSQL(DialectMySQL, "./testdata/sql_batch", 2)
+	if err != nil {
+		t.Errorf("DeltaSQL() error = %v", err)
+		return
+	}
+	want := "INSERT INTO `items` (`id`, `name`) VALUES\n" +
+		"(3, 'axe'),\n" +
+		"(4, 'bow');\n" +
+		"INSERT INTO `items` (`id`, `name`) VALUES\n" +
+		"(5, 'club');\n"
+	if got != want {
+		t.Errorf("DeltaSQL() got = %v, want %v", got, want)
+	}
+}
+
+func TestMasterData_sqlLiteral(t *testing.T) {
+	m := NewTabular("items", "csv", map[Key]string{}, false)
+	m.SetSchema(&Schema{
+		Columns: []Column{
+			{Name: "name", Type: TypeString},
+			{Name: "rate", Type: TypeFloat},
+			{Name: "start_at", Type: TypeDate, Layout: "2006/01/02 15:04"},
+		},
+		Empty: EmptyAsString,
+	})
+	tests := []struct {
+		name    string
+		column  string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "Date", column: "start_at", value: "2024/03/01 09:30", want: "'2024-03-01 09:30:00'"},
+		{name: "DateError", column: "start_at", value: "2024-03-01", wantErr: true},
+		{name: "Float", column: "rate", value: "1.50", want: "1.5"},
+		{name: "NaN", column: "rate", value: "NaN", wantErr: true},
+		{name: "Inf", column: "rate", value: "Inf", wantErr: true},
+		{name: "NegativeInf", column: "rate", value: "-Inf", wantErr: true},
+		{name: "EmptyString", column: "name", value: "", want: "''"},
+		{name: "EmptyFloat", column: "rate", value: "", want: "NULL"},
+		{name: "EmptyDate", column: "start_at", value: "", want: "NULL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.sqlLiteral(DialectMySQL, tt.column, tt.value, true)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("sqlLiteral() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("sqlLiteral() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,9 +10,11 @@ import (
 )
 
 // Key Make keys into structures to achieve multidimensional arrays.
+// Code holds the value of string or composite key columns, and is empty for a table keyed by the numeric id.
 type Key struct {
-	Id  int
-	Key string
+	Id   int
+	Key  string
+	Code string
 }
 
 // LoadMap Load the specified file and convert it to a map.
@@ -20,58 +22,236 @@ type Key struct {
 //
 //goland:noinspection GoUnusedExportedFunction
 func LoadMap(filePath string) (map[Key]string, error) {
+	return LoadMapWithSchema(filePath, nil)
+}
+
+// LoadMapWithSchema Load the specified file and convert it to a map, checking the values against the schema.
+// If the schema is nil, the values are not checked. If the file does not exist, return an empty map.
+//
+//goland:noinspection GoUnusedExportedFunction
+func LoadMapWithSchema(filePath string, schema *Schema) (map[Key]string, error) {
+	f, err := loadFile(filePath, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.rows, nil
+}
+
+// EditFile is a loaded table file, such as a file of an operation directory.
+type EditFile struct {
+	path    string
+	headers []string
+	rows    map[Key]string
+	// cleared is the keys whose value is the null marker. An update removes these values.
+	cleared map[Key]bool
+	// lineNumbers is the line number in the file of each row.
+	lineNumbers map[RowId]int
+}
+
+// newEditFile Create an empty table file.
+func newEditFile(filePath string) *EditFile {
+	return &EditFile{
+		path:        filePath,
+		rows:        make(map[Key]string),
+		cleared:     make(map[Key]bool),
+		lineNumbers: make(map[RowId]int),
+	}
+}
+
+// Path Get the path of the file.
+func (f *EditFile) Path() string {
+	return f.path
+}
+
+// Headers Get the columns of the file in order.
+func (f *EditFile) Headers() []string {
+	return f.headers
+}
+
+// Rows Get the values of the file. The map must not be changed.
+func (f *EditFile) Rows() map[Key]string {
+	return f.rows
+}
+
+// RowIds Get the ids of the rows of the file in ascending order.
+func (f *EditFile) RowIds() []RowId {
+	return PluckRowId(f.rows)
+}
+
+// IsCleared Check if the value of the key is the null marker.
+func (f *EditFile) IsCleared(key Key) bool {
+	return f.cleared[key]
+}
+
+// ClearedKeys Get the keys whose value is the null marker.
+func (f *EditFile) ClearedKeys() []Key {
+	keys := make([]Key, 0, len(f.cleared))
+	for key := range f.cleared {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// NewError Create a problem about the specified row of the file.
+func (f *EditFile) NewError(kind ErrorKind, id RowId, message string) ValidationError {
+	return ValidationError{
+		Kind:     kind,
+		FilePath: f.path,
+		Line:     f.lineNumbers[id],
+		Id:       id.String(),
+		Message:  message,
+	}
+}
+
+// loadFile Load the specified file and convert it to a map, together with the header and the line numbers.
+func loadFile(filePath string, schema *Schema) (*EditFile, error) {
 	if !supportFile.Exists(filePath) {
-		return make(map[Key]string), nil
+		return newEditFile(filePath), nil
 	}
 
-	rows, err := delimited.Load(filePath, true, true)
+	rows, lineNumbers, err := delimited.LoadWithLineNumbers(filePath, true, true)
 	if err != nil {
 		return nil, err
 	}
 
-	return convertMap(rows, filePath)
+	return convertMap(rows, lineNumbers, filePath, schema)
 }
 
 // convertMap
 // Replacing separated value data (two-dimensional array of height and width) into a multidimensional associative array in a format
 // that facilitates direct value specification by key.
-func convertMap(rows [][]string, filepath string) (map[Key]string, error) {
-	convertedData := make(map[Key]string)
+// Every problem in the file is reported together as ValidationErrors.
+func convertMap(rows [][]string, lineNumbers []int, filepath string, schema *Schema) (*EditFile, error) {
+	f := newEditFile(filepath)
+	var validationErrors ValidationErrors
 	keyName := map[int]string{}
-	findIdColumn := false
-	idColumnNumber := 0
+	keyColumnNumbers := map[string]int{}
+	isKeyColumn := map[string]bool{}
+	for _, keyColumn := range schema.keyColumns() {
+		isKeyColumn[keyColumn] = true
+	}
 
 	for rowNumber, row := range rows {
-		for columnNumber, value := range row {
-			// The first line is the key.
-			if rowNumber == 0 {
-				if value == "id" {
-					findIdColumn = true
-					idColumnNumber = columnNumber
-				}
+		// The first line is the key.
+		if rowNumber == 0 {
+			f.headers = row
+			for columnNumber, value := range row {
 				keyName[columnNumber] = value
-				continue
+				keyColumnNumbers[value] = columnNumber
+			}
+			for _, keyColumn := range schema.keyColumns() {
+				if _, ok := keyColumnNumbers[keyColumn]; !ok {
+					return nil, ValidationErrors{{
+						Kind:     KindMissingKeyColumn,
+						FilePath: filepath,
+						Column:   keyColumn,
+						Message:  "Not found " + keyColumn + " column",
+					}}
+				}
+			}
+			continue
+		}
+
+		line := rowNumber + 1
+		if lineNumbers != nil {
+			line = lineNumbers[rowNumber]
+		}
+		newError := func(kind ErrorKind, id string, column string, value string, message string) ValidationError {
+			return ValidationError{
+				Kind:      kind,
+				FilePath:  filepath,
+				Line:      line,
+				RowNumber: rowNumber,
+				Id:        id,
+				Column:    column,
+				Value:     value,
+				Message:   message,
 			}
+		}
+
+		rowId, kind, err := parseRowId(row, keyColumnNumbers, schema)
+		if err != nil {
+			validationErrors = append(validationErrors, newError(kind, "", "", "", err.Error()))
+			continue
+		}
+		f.lineNumbers[rowId] = line
 
-			id, err := strconv.Atoi(row[idColumnNumber])
-			if err != nil {
-				return nil, errors.New("ID is not numeric : " + filepath)
+		for columnNumber, value := range row {
+			columnName := keyName[columnNumber]
+			key := rowId.Key(columnName)
+			if _, flg := f.rows[key]; flg || f.cleared[key] {
+				validationErrors = append(validationErrors, newError(KindDuplicateKey, rowId.String(), columnName, value, "Duplicate key"))
+				break
 			}
-			if _, flg := convertedData[Key{id, keyName[columnNumber]}]; flg {
-				return nil, errors.New("Duplicate key : " + filepath + " rowNumber : " + strconv.Itoa(rowNumber))
+
+			emptyPolicy := schema.emptyPolicy(columnName)
+			if schema.isNullMarker(value) && !isKeyColumn[columnName] {
+				if emptyPolicy == EmptyReject {
+					validationErrors = append(validationErrors, newError(KindEmptyValue, rowId.String(), columnName, value, "NULL is not allowed"))
+				} else {
+					f.cleared[key] = true
+				}
+				continue
 			}
+
 			if value == "" {
-				return nil, errors.New("Empty value : " + filepath + " rowNumber : " + strconv.Itoa(rowNumber))
+				switch emptyPolicy {
+				case EmptyReject:
+					validationErrors = append(validationErrors, newError(KindEmptyValue, rowId.String(), columnName, value, "Empty value"))
+					continue
+				case EmptyAsAbsent:
+					continue
+				case EmptyAsDefault:
+					value = schema.defaultValue(columnName)
+				}
+			}
+			f.rows[key] = value
+
+			if schema != nil && value != "" {
+				if column, ok := schema.Column(columnName); ok {
+					if err = column.Validate(value); err != nil {
+						validationErrors = append(validationErrors, newError(KindInvalidValue, rowId.String(), columnName, value, err.Error()))
+					}
+				}
 			}
-			convertedData[Key{id, keyName[columnNumber]}] = value
 		}
 	}
 
-	if !findIdColumn {
-		return nil, errors.New("Not found id column : " + filepath)
+	if len(rows) == 0 {
+		return nil, ValidationErrors{{Kind: KindMissingKeyColumn, FilePath: filepath, Message: "Not found id column"}}
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, validationErrors
+	}
+
+	return f, nil
+}
+
+// parseRowId Get the id of the row from the values of the key columns.
+// If the values are invalid, the kind of the problem is returned together.
+func parseRowId(row []string, keyColumnNumbers map[string]int, schema *Schema) (RowId, ErrorKind, error) {
+	keyColumns := schema.keyColumns()
+	if schema.isNumericKey() {
+		id, err := strconv.Atoi(row[keyColumnNumbers[keyColumns[0]]])
+		if err != nil {
+			return RowId{}, KindNotNumericId, errors.New("ID is not numeric")
+		}
+		return RowId{Id: id}, "", nil
+	}
+
+	values := make([]string, 0, len(keyColumns))
+	for _, keyColumn := range keyColumns {
+		value := row[keyColumnNumbers[keyColumn]]
+		if value == "" {
+			return RowId{}, KindEmptyKey, errors.New("Empty key : " + keyColumn)
+		}
+		values = append(values, value)
 	}
 
-	return convertedData, nil
+	return codeRowId(values...), "", nil
 }
 
 // PluckId Pluck the ID from the map.
@@ -0,0 +1,118 @@
+package table
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/go-support-tool/array"
+	"github.com/stepupdream/go-support-tool/name"
+)
+
+// VersionError is returned when the application of a version directory fails.
+type VersionError struct {
+	Version string
+	Err     error
+}
+
+// Error returns the error message including the failed version.
+func (e *VersionError) Error() string {
+	return "Failed to apply version : " + e.Version + " : " + e.Err.Error()
+}
+
+// Unwrap returns the error that caused the failure.
+func (e *VersionError) Unwrap() error {
+	return e.Err
+}
+
+// Replay Create a new MasterData and apply every version directory up to the specified version.
+// If toVersion is empty, every version in the root directory is applied.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Replay(name string, extensionName string, isPartialMatch bool, rootDirectoryPath string, fromVersion string, toVersion string) (*MasterData, error) {
+	m := NewTabular(name, extensionName, make(map[Key]string), isPartialMatch)
+	if err := m.LoadByVersionRange(rootDirectoryPath, fromVersion, toVersion); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// LoadByVersion Load every version directory in the root directory up to the specified version.
+func (m *MasterData) LoadByVersion(rootDirectoryPath string, targetVersion string) error {
+	return m.LoadByVersionRange(rootDirectoryPath, "", targetVersion)
+}
+
+// LoadByVersionRange Load the version directories from fromVersion to toVersion in numeric order.
+// The root directory path must be the path to the directory containing the version directories (ex. 1_0_0_0).
+// If fromVersion is empty, it starts with the oldest version. If toVersion is empty, it ends with the latest version.
+func (m *MasterData) LoadByVersionRange(rootDirectoryPath string, fromVersion string, toVersion string) error {
+	return m.loadByVersionRange(context.Background(), rootDirectoryPath, fromVersion, toVersion)
+}
+
+// loadByVersionRange Load the version directories as LoadByVersionRange does, and stop before the next version when the context is canceled.
+// The versions loaded before the cancel stay applied.
+func (m *MasterData) loadByVersionRange(ctx context.Context, rootDirectoryPath string, fromVersion string, toVersion string) error {
+	versions, err := VersionNames(rootDirectoryPath, fromVersion, toVersion)
+	if err != nil {
+		return err
+	}
+
+	pathSeparator := string(os.PathSeparator)
+	for _, version := range versions {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if err = m.LoadByDirectoryPath(rootDirectoryPath + pathSeparator + version); err != nil {
+			return &VersionError{Version: version, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// VersionNames Get the names of the version directories from fromVersion to toVersion in numeric order.
+// If fromVersion is empty, it starts with the oldest version. If toVersion is empty, it ends with the latest version.
+func VersionNames(rootDirectoryPath string, fromVersion string, toVersion string) ([]string, error) {
+	dirEntries, err := os.ReadDir(rootDirectoryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			names = append(names, dirEntry.Name())
+		}
+	}
+
+	sortedNames, err := name.SortByNumericSegments(names)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid version directory : "+rootDirectoryPath)
+	}
+
+	for _, version := range []string{fromVersion, toVersion} {
+		if version != "" && !array.Contains(sortedNames, version) {
+			return nil, errors.New("The specified version could not be found : " + version)
+		}
+	}
+
+	var r []string
+	for _, version := range sortedNames {
+		if fromVersion != "" {
+			isGreater, _ := name.IsGreaterVersion(fromVersion, version)
+			if isGreater {
+				continue
+			}
+		}
+		if toVersion != "" {
+			isGreater, _ := name.IsGreaterVersion(version, toVersion)
+			if isGreater {
+				break
+			}
+		}
+		r = append(r, version)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,169 @@
+package table
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestVersionNames(t *testing.T) {
+	type args struct {
+		rootDirectoryPath string
+		fromVersion       string
+		toVersion         string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "VersionNames1",
+			args: args{
+				rootDirectoryPath: "./testdata/versions",
+			},
+			want:    []string{"1_0_0_0", "1_0_1_0", "1_0_2_0", "1_0_10_0"},
+			wantErr: false,
+		},
+		{
+			name: "VersionNames2",
+			args: args{
+				rootDirectoryPath: "./testdata/versions",
+				fromVersion:       "1_0_1_0",
+				toVersion:         "1_0_2_0",
+			},
+			want:    []string{"1_0_1_0", "1_0_2_0"},
+			wantErr: false,
+		},
+		{
+			name: "VersionNames3",
+			args: args{
+				rootDirectoryPath: "./testdata/versions",
+				toVersion:         "9_0_0_0",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VersionNames(tt.args.rootDirectoryPath, tt.args.fromVersion, tt.args.toVersion)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VersionNames() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("VersionNames() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadByVersionRange(t *testing.T) {
+	type args struct {
+		rootDirectoryPath string
+		fromVersion       string
+		toVersion         string
+	}
+	tests := []struct {
+		name        string
+		rows        map[Key]string
+		args        args
+		want        map[Key]string
+		wantVersion string
+	}{
+		{
+			name: "LoadByVersionRange1",
+			rows: map[Key]string{},
+			args: args{
+				rootDirectoryPath: "./testdata/versions",
+			},
+			want: map[Key]string{
+				{Id: 2, Key: "id"}:       "2",
+				{Id: 2, Key: "sample"}:   "ccc",
+				{Id: 2, Key: "level"}:    "700",
+				{Id: 100, Key: "id"}:     "100",
+				{Id: 100, Key: "sample"}: "AAA",
+				{Id: 100, Key: "level"}:  "1000",
+			},
+		},
+		{
+			name: "LoadByVersionRange2",
+			rows: map[Key]string{},
+			args: args{
+				rootDirectoryPath: "./testdata/versions",
+				toVersion:         "1_0_1_0",
+			},
+			want: map[Key]string{
+				{Id: 1, Key: "id"}:     "1",
+				{Id: 1, Key: "sample"}: "aaa",
+				{Id: 1, Key: "level"}:  "5",
+				{Id: 2, Key: "id"}:     "2",
+				{Id: 2, Key: "sample"}: "ccc",
+				{Id: 2, Key: "level"}:  "700",
+			},
+		},
+		{
+			name: "LoadByVersionRange3",
+			rows: map[Key]string{
+				{Id: 1, Key: "id"}:     "1",
+				{Id: 1, Key: "sample"}: "aaa",
+				{Id: 1, Key: "level"}:  "5",
+			},
+			args: args{
+				rootDirectoryPath: "./testdata/versions",
+				fromVersion:       "1_0_2_0",
+				toVersion:         "1_0_2_0",
+			},
+			want: map[Key]string{},
+		},
+		{
+			name: "LoadByVersionRange4",
+			rows: map[Key]string{},
+			args: args{
+				rootDirectoryPath: "./testdata/versions_error",
+			},
+			wantVersion: "1_0_1_0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewTabular("samples", "csv", tt.rows, false)
+			err := m.LoadByVersionRange(tt.args.rootDirectoryPath, tt.args.fromVersion, tt.args.toVersion)
+			if tt.wantVersion != "" {
+				var versionError *VersionError
+				if !errors.As(err, &versionError) || versionError.Version != tt.wantVersion {
+					t.Errorf("LoadByVersionRange() error = %v, wantVersion %v", err, tt.wantVersion)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("LoadByVersionRange() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(m.Rows, tt.want) {
+				t.Errorf("LoadByVersionRange() got = %v, want %v", m.Rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplay(t *testing.T) {
+	got, err := Replay("samples", "csv", false, "./testdata/versions", "", "1_0_0_0")
+	if err != nil {
+		t.Errorf("Replay() error = %v", err)
+		return
+	}
+	want := map[Key]string{
+		{Id: 1, Key: "id"}:     "1",
+		{Id: 1, Key: "sample"}: "aaa",
+		{Id: 1, Key: "level"}:  "5",
+		{Id: 2, Key: "id"}:     "2",
+		{Id: 2, Key: "sample"}: "bbb",
+		{Id: 2, Key: "level"}:  "43",
+	}
+	if !reflect.DeepEqual(got.Rows, want) {
+		t.Errorf("Replay() got = %v, want %v", got.Rows, want)
+	}
+}
@@ -0,0 +1,266 @@
+package table
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TimeWindow is the declaration of the columns that hold the validity window of each row. (ex. start_at, end_at)
+// A row is active from the start, inclusive, to the end, exclusive.
+// An empty or missing start means the row has always been active, and an empty or missing end means it never ends.
+type TimeWindow struct {
+	StartColumn string
+	EndColumn   string
+	// GroupColumns is the columns whose values make a group. The windows of the rows in a group must not overlap.
+	// If empty, the windows are not checked for overlap.
+	GroupColumns []string
+	// Layout is the layout of the values. If empty, the layout of the date column of the schema, or DateLayout, is used.
+	Layout string
+	// Location is the location in which the values are read when no location is given,
+	// such as when a load validates the windows. If nil, UTC is used.
+	Location *time.Location
+}
+
+// location Get the location in which the values are read when no location is given.
+func (w *TimeWindow) location() *time.Location {
+	if w.Location == nil {
+		return time.UTC
+	}
+
+	return w.Location
+}
+
+// rowWindow is the validity window of a row.
+type rowWindow struct {
+	id       RowId
+	group    string
+	start    time.Time
+	end      time.Time
+	hasStart bool
+	hasEnd   bool
+}
+
+// before Check if the window starts before the other.
+func (w rowWindow) before(other rowWindow) bool {
+	if !w.hasStart || !other.hasStart {
+		return !w.hasStart && other.hasStart
+	}
+
+	return w.start.Before(other.start)
+}
+
+// endsAfter Check if the window ends after the other.
+func (w rowWindow) endsAfter(other rowWindow) bool {
+	if !w.hasEnd || !other.hasEnd {
+		return !w.hasEnd && other.hasEnd
+	}
+
+	return w.end.After(other.end)
+}
+
+// overlaps Check if the window, which starts before the other, is still active when the other starts.
+func (w rowWindow) overlaps(other rowWindow) bool {
+	return !w.hasEnd || !other.hasStart || w.end.After(other.start)
+}
+
+// activeAt Check if the row is active at the time.
+func (w rowWindow) activeAt(at time.Time) bool {
+	return (!w.hasStart || !at.Before(w.start)) && (!w.hasEnd || at.Before(w.end))
+}
+
+// ValidateWindows Check that the window of each row is well formed, and that the windows of a group do not overlap.
+// The values are read in the location. If the location is nil, the location of the TimeWindow is used.
+// If the schema does not declare a window, nothing is checked.
+func (m *MasterData) ValidateWindows(location *time.Location) error {
+	_, validationErrors := m.windows(location)
+	return validationErrors.orNil()
+}
+
+// ActiveRowIds Get the ids of the rows that are active at the time, in ascending order.
+// The values of the window columns are read in the location, so that the same values mean the same local time
+// in every timezone. If the location is nil, the location of the TimeWindow is used.
+func (m *MasterData) ActiveRowIds(at time.Time, location *time.Location) ([]RowId, error) {
+	windows, validationErrors := m.windows(location)
+	if err := validationErrors.orNil(); err != nil {
+		return nil, err
+	}
+
+	var r []RowId
+	for _, w := range windows {
+		if w.activeAt(at) {
+			r = append(r, w.id)
+		}
+	}
+
+	return r, nil
+}
+
+// ActiveAt Create a snapshot of the table that has only the rows active at the time.
+// See ActiveRowIds for the location.
+func (m *MasterData) ActiveAt(at time.Time, location *time.Location) (*MasterData, error) {
+	ids, err := m.ActiveRowIds(at, location)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &MasterData{
+		name:           m.name,
+		isPartialMatch: m.isPartialMatch,
+		extension:      m.extension,
+		updateMode:     m.updateMode,
+		errorMode:      m.errorMode,
+		schema:         m.schema,
+		columns:        append([]string{}, m.columns...),
+		operations:     m.operations,
+		localization:   m.localization,
+		Rows:           make(map[Key]string),
+	}
+	for _, id := range ids {
+		for column, value := range m.Row(id) {
+			snapshot.Rows[id.Key(column)] = value
+		}
+	}
+
+	return snapshot, nil
+}
+
+// WindowChanges Get the times when the set of the active rows changes, in ascending order.
+// See ActiveRowIds for the location.
+func (m *MasterData) WindowChanges(location *time.Location) ([]time.Time, error) {
+	windows, validationErrors := m.windows(location)
+	if err := validationErrors.orNil(); err != nil {
+		return nil, err
+	}
+
+	var r []time.Time
+	for _, w := range windows {
+		if w.hasStart {
+			r = append(r, w.start)
+		}
+		if w.hasEnd {
+			r = append(r, w.end)
+		}
+	}
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Before(r[j])
+	})
+
+	unique := r[:0]
+	for i, t := range r {
+		if i == 0 || !t.Equal(r[i-1]) {
+			unique = append(unique, t)
+		}
+	}
+
+	return unique, nil
+}
+
+// windows Get the window of each row in the order of the row id, with the problems of the windows.
+func (m *MasterData) windows(location *time.Location) ([]rowWindow, ValidationErrors) {
+	if m.schema == nil || m.schema.Window == nil {
+		return nil, nil
+	}
+
+	window := m.schema.Window
+	if location == nil {
+		location = window.location()
+	}
+	var windows []rowWindow
+	var validationErrors ValidationErrors
+	for _, id := range m.RowIds() {
+		w := rowWindow{id: id}
+		var err error
+		if w.start, w.hasStart, err = m.windowTime(id, window.StartColumn, location); err != nil {
+			validationErrors = append(validationErrors, m.windowError(KindInvalidWindow, id, window.StartColumn, err.Error()))
+			continue
+		}
+		if w.end, w.hasEnd, err = m.windowTime(id, window.EndColumn, location); err != nil {
+			validationErrors = append(validationErrors, m.windowError(KindInvalidWindow, id, window.EndColumn, err.Error()))
+			continue
+		}
+		if w.hasStart && w.hasEnd && !w.start.Before(w.end) {
+			validationErrors = append(validationErrors, m.windowError(KindInvalidWindow, id, window.EndColumn, "Window ends before it starts"))
+			continue
+		}
+
+		values := make([]string, 0, len(window.GroupColumns))
+		for _, column := range window.GroupColumns {
+			values = append(values, m.Rows[id.Key(column)])
+		}
+		w.group = joinCode(values)
+		windows = append(windows, w)
+	}
+
+	if len(window.GroupColumns) > 0 {
+		validationErrors = append(validationErrors, m.checkOverlap(windows)...)
+	}
+
+	return windows, validationErrors
+}
+
+// checkOverlap Check that the windows of a group do not overlap.
+func (m *MasterData) checkOverlap(windows []rowWindow) ValidationErrors {
+	groups := make(map[string][]rowWindow)
+	var groupNames []string
+	for _, w := range windows {
+		if _, ok := groups[w.group]; !ok {
+			groupNames = append(groupNames, w.group)
+		}
+		groups[w.group] = append(groups[w.group], w)
+	}
+
+	var validationErrors ValidationErrors
+	for _, groupName := range groupNames {
+		groupWindows := groups[groupName]
+		sort.SliceStable(groupWindows, func(i, j int) bool {
+			return groupWindows[i].before(groupWindows[j])
+		})
+
+		// latest is the window that ends last among the windows that start earlier.
+		latest := groupWindows[0]
+		for _, w := range groupWindows[1:] {
+			if latest.overlaps(w) {
+				message := "Window overlaps with id : " + latest.id.String()
+				validationErrors = append(validationErrors, m.windowError(KindOverlappingWindow, w.id, m.schema.Window.StartColumn, message))
+			}
+			if w.endsAfter(latest) {
+				latest = w
+			}
+		}
+	}
+
+	return validationErrors
+}
+
+// windowTime Parse the value of the window column of the row. An empty or missing value does not have a time.
+func (m *MasterData) windowTime(id RowId, column string, location *time.Location) (time.Time, bool, error) {
+	value := m.Rows[id.Key(column)]
+	if value == "" {
+		return time.Time{}, false, nil
+	}
+
+	layout := m.schema.Window.Layout
+	if layout == "" {
+		schemaColumn, _ := m.schema.Column(column)
+		layout = schemaColumn.layout()
+	}
+	t, err := time.ParseInLocation(layout, value, location)
+	if err != nil {
+		return time.Time{}, false, errors.New("Value is not date : " + value)
+	}
+
+	return t, true, nil
+}
+
+// windowError Create a problem about the window of the row.
+func (m *MasterData) windowError(kind ErrorKind, id RowId, column string, message string) ValidationError {
+	return ValidationError{
+		Kind:    kind,
+		Id:      id.String(),
+		Column:  column,
+		Value:   m.Rows[id.Key(column)],
+		Message: message,
+	}
+}
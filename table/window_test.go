@@ -0,0 +1,146 @@
+package table
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testWindowTable(t *testing.T) *MasterData {
+	schema, err := LoadSchema("./testdata/window/schema.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewTabular("events", "csv", map[Key]string{}, false)
+	m.SetSchema(schema)
+	if err = m.LoadByDirectoryPath("./testdata/window"); err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
+
+func TestLoadSchema_Window(t *testing.T) {
+	schema, err := LoadSchema("./testdata/window/schema.csv")
+	if err != nil {
+		t.Errorf("LoadSchema() error = %v", err)
+		return
+	}
+	want := &TimeWindow{StartColumn: "start_at", EndColumn: "end_at", GroupColumns: []string{"event_type"}}
+	if !reflect.DeepEqual(schema.Window, want) {
+		t.Errorf("LoadSchema() got = %v, want %v", schema.Window, want)
+	}
+}
+
+func TestMasterData_ActiveRowIds(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	tests := []struct {
+		name     string
+		at       time.Time
+		location *time.Location
+		want     []RowId
+	}{
+		{name: "ActiveRowIds1", at: time.Date(2023, 1, 4, 15, 30, 0, 0, time.UTC), location: nil, want: []RowId{{Id: 1}, {Id: 3}}},
+		{name: "ActiveRowIds2", at: time.Date(2023, 1, 4, 15, 30, 0, 0, time.UTC), location: tokyo, want: []RowId{{Id: 1}}},
+		{name: "ActiveRowIds3", at: time.Date(2023, 1, 8, 0, 0, 0, 0, time.UTC), location: nil, want: []RowId{{Id: 2}}},
+		{name: "ActiveRowIds4", at: time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC), location: nil, want: []RowId{{Id: 3}}},
+	}
+	m := testWindowTable(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.ActiveRowIds(tt.at, tt.location)
+			if err != nil {
+				t.Errorf("ActiveRowIds() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ActiveRowIds() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	snapshot, err := m.ActiveAt(time.Date(2023, 1, 8, 0, 0, 0, 0, time.UTC), nil)
+	if err != nil {
+		t.Errorf("ActiveAt() error = %v", err)
+		return
+	}
+	want := [][]string{{"id", "event_type", "start_at", "end_at"}, {"2", "login", "2023-01-08 00:00:00", ""}}
+	if got := snapshot.ToRows(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ActiveAt() got = %v, want %v", got, want)
+	}
+}
+
+func TestMasterData_WindowChanges(t *testing.T) {
+	got, err := testWindowTable(t).WindowChanges(nil)
+	if err != nil {
+		t.Errorf("WindowChanges() error = %v", err)
+		return
+	}
+	want := []time.Time{
+		time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 8, 0, 0, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowChanges() got = %v, want %v", got, want)
+	}
+}
+
+func TestMasterData_ValidateWindows(t *testing.T) {
+	schema, err := LoadSchema("./testdata/window/schema.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewTabular("events", "csv", map[Key]string{}, false)
+	m.SetSchema(schema)
+
+	err = m.LoadByDirectoryPath("./testdata/window_error")
+	var validationErrors ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		t.Errorf("LoadByDirectoryPath() error = %v, want ValidationErrors", err)
+		return
+	}
+	var got []string
+	for _, validationError := range validationErrors {
+		got = append(got, string(validationError.Kind)+" "+validationError.Id)
+	}
+	if want := []string{"invalid_window 3", "overlapping_window 2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadByDirectoryPath() error = %v, want %v", got, want)
+	}
+	if len(m.Rows) != 0 {
+		t.Errorf("LoadByDirectoryPath() changed the table : %v", m.Rows)
+	}
+
+	if err = testWindowTable(t).ValidateWindows(nil); err != nil {
+		t.Errorf("ValidateWindows() error = %v", err)
+	}
+}
+
+func TestMasterData_ValidateWindows_GroupSeparator(t *testing.T) {
+	m := NewTabular("events", "csv", map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "a"}: "a:b", {Id: 1, Key: "b"}: "c",
+		{Id: 1, Key: "start_at"}: "2023-01-01 00:00:00", {Id: 1, Key: "end_at"}: "2023-01-10 00:00:00",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "a"}: "a", {Id: 2, Key: "b"}: "b:c",
+		{Id: 2, Key: "start_at"}: "2023-01-05 00:00:00", {Id: 2, Key: "end_at"}: "2023-01-15 00:00:00",
+	}, false)
+	m.SetSchema(&Schema{Window: &TimeWindow{StartColumn: "start_at", EndColumn: "end_at", GroupColumns: []string{"a", "b"}}})
+
+	if err := m.ValidateWindows(nil); err != nil {
+		t.Errorf("ValidateWindows() error = %v", err)
+	}
+}
+
+func TestMasterData_ActiveRowIds_WindowLocation(t *testing.T) {
+	m := testWindowTable(t)
+	m.Schema().Window.Location = time.FixedZone("JST", 9*60*60)
+
+	got, err := m.ActiveRowIds(time.Date(2023, 1, 4, 15, 30, 0, 0, time.UTC), nil)
+	if err != nil {
+		t.Errorf("ActiveRowIds() error = %v", err)
+		return
+	}
+	if want := []RowId{{Id: 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ActiveRowIds() got = %v, want %v", got, want)
+	}
+}